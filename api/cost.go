@@ -0,0 +1,317 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CostCalculator prices usage by the cost of each cluster's nodes. Costs are
+// computed with exact decimal arithmetic, except where a division is required,
+// which is rounded to 16 decimal places.
+type CostCalculator struct {
+	clusters map[string]*Cluster
+}
+
+// NewCostCalculator creates a calculator for the given clusters. Clusters are
+// looked up by ID, full name, or short name where the short name is unique.
+func NewCostCalculator(clusters []Cluster) *CostCalculator {
+	c := &CostCalculator{clusters: map[string]*Cluster{}}
+	names := map[string]int{}
+	for i := range clusters {
+		names[clusters[i].Name]++
+	}
+	for i := range clusters {
+		cluster := &clusters[i]
+		c.clusters[cluster.ID] = cluster
+		c.clusters[cluster.FullName] = cluster
+		if names[cluster.Name] == 1 {
+			if _, ok := c.clusters[cluster.Name]; !ok {
+				c.clusters[cluster.Name] = cluster
+			}
+		}
+	}
+	delete(c.clusters, "")
+	return c
+}
+
+// UsageCost breaks down the cost of a usage report.
+type UsageCost struct {
+	// Total is the cost of all priced usage.
+	Total decimal.Decimal
+
+	// ByCluster, ByWorkspace and ByAuthor total the cost of each cluster,
+	// workspace and author. Workspaces and authors are only included if the
+	// report is grouped by them.
+	ByCluster   map[string]decimal.Decimal
+	ByWorkspace map[string]decimal.Decimal
+	ByAuthor    map[string]decimal.Decimal
+
+	// Intervals total the cost of all series in each interval, in order.
+	Intervals []IntervalCost
+
+	// Unpriced lists clusters whose usage isn't included because they're
+	// unknown or have no node cost.
+	Unpriced []string
+}
+
+// IntervalCost is the cost of usage within a single interval.
+type IntervalCost struct {
+	Start time.Time
+	End   time.Time
+	Cost  decimal.Decimal
+}
+
+// NodeUsageCost prices a node usage report which measures the given metric.
+// The report must be grouped by cluster.
+//
+// Node hours are priced at the node cost. CPU and GPU hours are priced at the
+// node cost divided by the number of CPUs or GPUs in each node.
+func (c *CostCalculator) NodeUsageCost(r *NodeUsageReport, metric NodeMetric) (*UsageCost, error) {
+	b := newCostBuilder()
+	for _, s := range r.Series {
+		if err := c.addSeries(b, string(metric), s.Cluster, "", "", s.Intervals, s.Totals); err != nil {
+			return nil, err
+		}
+	}
+	return b.result(), nil
+}
+
+// TaskUsageCost prices a task usage report which measures the given metric.
+// The report must be grouped by cluster. Spend is also attributed to
+// workspaces and authors if the report is grouped by them.
+//
+// Task hours are priced as if each task occupied an entire node. CPU and GPU
+// hours are priced at the node cost divided by the number of CPUs or GPUs in
+// each node.
+func (c *CostCalculator) TaskUsageCost(r *TaskUsageReport, metric TaskMetric) (*UsageCost, error) {
+	b := newCostBuilder()
+	for _, s := range r.Series {
+		if err := c.addSeries(b, string(metric), s.Cluster, s.Workspace, s.Author, s.Intervals, s.Totals); err != nil {
+			return nil, err
+		}
+	}
+	return b.result(), nil
+}
+
+func (c *CostCalculator) addSeries(
+	b *costBuilder,
+	metric string,
+	cluster, workspace, author string,
+	intervals []UsageInterval,
+	totals UsageInterval,
+) error {
+	if cluster == "" {
+		return errors.New("usage must be grouped by cluster to compute its cost")
+	}
+
+	price, ok, err := c.unitPrice(cluster, metric)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		b.unpriced[cluster] = true
+		return nil
+	}
+
+	var cost decimal.Decimal
+	if len(intervals) == 0 {
+		cost = price.Mul(decimal.NewFromFloat(totals.Value))
+	}
+	for _, i := range intervals {
+		intervalCost := price.Mul(decimal.NewFromFloat(i.Value))
+		b.addInterval(i.Start, i.End, intervalCost)
+		cost = cost.Add(intervalCost)
+	}
+
+	b.total = b.total.Add(cost)
+	addCost(b.byCluster, cluster, cost)
+	if workspace != "" {
+		addCost(b.byWorkspace, workspace, cost)
+	}
+	if author != "" {
+		addCost(b.byAuthor, author, cost)
+	}
+	return nil
+}
+
+// unitPrice returns the cost of one unit of a metric on a cluster. It returns
+// false if the cluster is unknown or has no node cost.
+func (c *CostCalculator) unitPrice(ref string, metric string) (decimal.Decimal, bool, error) {
+	cluster, ok := c.clusters[ref]
+	if !ok || cluster.NodeCost == nil {
+		return decimal.Zero, false, nil
+	}
+
+	switch metric {
+	case string(NodeMetricHours):
+		return *cluster.NodeCost, true, nil
+	case string(NodeMetricCPUHours):
+		if cluster.NodeSpec.CPUCount <= 0 {
+			return decimal.Zero, false, fmt.Errorf("cluster %q has no CPUs", ref)
+		}
+		return cluster.NodeCost.Div(decimal.NewFromFloat(cluster.NodeSpec.CPUCount)), true, nil
+	case string(NodeMetricGPUHours):
+		if cluster.NodeSpec.GPUCount <= 0 {
+			return decimal.Zero, false, fmt.Errorf("cluster %q has no GPUs", ref)
+		}
+		return cluster.NodeCost.Div(decimal.NewFromInt(int64(cluster.NodeSpec.GPUCount))), true, nil
+	default:
+		return decimal.Zero, false, fmt.Errorf("can't compute the cost of metric %q", metric)
+	}
+}
+
+// costBuilder accumulates the cost of a usage report.
+type costBuilder struct {
+	total       decimal.Decimal
+	byCluster   map[string]decimal.Decimal
+	byWorkspace map[string]decimal.Decimal
+	byAuthor    map[string]decimal.Decimal
+	intervals   map[time.Time]*IntervalCost
+	unpriced    map[string]bool
+}
+
+func newCostBuilder() *costBuilder {
+	return &costBuilder{
+		byCluster:   map[string]decimal.Decimal{},
+		byWorkspace: map[string]decimal.Decimal{},
+		byAuthor:    map[string]decimal.Decimal{},
+		intervals:   map[time.Time]*IntervalCost{},
+		unpriced:    map[string]bool{},
+	}
+}
+
+func (b *costBuilder) addInterval(start, end time.Time, cost decimal.Decimal) {
+	key := start.UTC()
+	if i, ok := b.intervals[key]; ok {
+		i.Cost = i.Cost.Add(cost)
+		return
+	}
+	b.intervals[key] = &IntervalCost{Start: start, End: end, Cost: cost}
+}
+
+func (b *costBuilder) result() *UsageCost {
+	result := &UsageCost{
+		Total:       b.total,
+		ByCluster:   b.byCluster,
+		ByWorkspace: b.byWorkspace,
+		ByAuthor:    b.byAuthor,
+	}
+	for _, i := range b.intervals {
+		result.Intervals = append(result.Intervals, *i)
+	}
+	sort.Slice(result.Intervals, func(i, j int) bool {
+		return result.Intervals[i].Start.Before(result.Intervals[j].Start)
+	})
+	for cluster := range b.unpriced {
+		result.Unpriced = append(result.Unpriced, cluster)
+	}
+	sort.Strings(result.Unpriced)
+	return result
+}
+
+func addCost(m map[string]decimal.Decimal, key string, cost decimal.Decimal) {
+	m[key] = m[key].Add(cost)
+}
+
+// SpecCost is the estimated cost of running an experiment.
+type SpecCost struct {
+	// Total is the cost of all tasks.
+	Total decimal.Decimal
+
+	// Tasks are the costs of each task, in the order of the spec.
+	Tasks []TaskCost
+}
+
+// TaskCost is the estimated cost of running a single task.
+type TaskCost struct {
+	Name    string
+	Cluster string
+
+	// Share is the fraction of a node which the task's resource requests
+	// reserve, according to the most constrained resource.
+	Share decimal.Decimal
+
+	Cost decimal.Decimal
+}
+
+// EstimateSpec estimates the cost of running each of a spec's tasks for the
+// given duration. A task's cost is the share of a node its resource requests
+// reserve, multiplied by the node cost of the task's cluster. Tasks which
+// don't request resources are priced as an entire node.
+//
+// It's an error for a task to run on a cluster which is unknown or has no
+// node cost, or to request more resources than a node provides.
+func (c *CostCalculator) EstimateSpec(spec *ExperimentSpecV2, duration time.Duration) (*SpecCost, error) {
+	if duration < 0 {
+		return nil, errors.New("duration must not be negative")
+	}
+	hours := decimal.NewFromInt(int64(duration)).Div(decimal.NewFromInt(int64(time.Hour)))
+
+	result := &SpecCost{}
+	for i, task := range spec.Tasks {
+		name := task.Name
+		if name == "" {
+			name = fmt.Sprintf("tasks[%d]", i)
+		}
+
+		cluster, ok := c.clusters[task.Context.Cluster]
+		if !ok {
+			return nil, fmt.Errorf("task %s: cluster %q not found", name, task.Context.Cluster)
+		}
+		if cluster.NodeCost == nil {
+			return nil, fmt.Errorf("task %s: cluster %q has no node cost", name, task.Context.Cluster)
+		}
+
+		share, err := nodeShare(task.Resources, &cluster.NodeSpec)
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", name, err)
+		}
+
+		cost := cluster.NodeCost.Mul(share).Mul(hours)
+		result.Tasks = append(result.Tasks, TaskCost{
+			Name:    task.Name,
+			Cluster: task.Context.Cluster,
+			Share:   share,
+			Cost:    cost,
+		})
+		result.Total = result.Total.Add(cost)
+	}
+	return result, nil
+}
+
+// nodeShare computes the fraction of a node reserved by a resource request.
+func nodeShare(req *ResourceRequest, node *NodeResources) (decimal.Decimal, error) {
+	one := decimal.NewFromInt(1)
+	if req == nil {
+		return one, nil
+	}
+
+	var shares []decimal.Decimal
+	if req.CPUCount > 0 {
+		if req.CPUCount > node.CPUCount {
+			return decimal.Zero, fmt.Errorf("requested %v CPUs but nodes have %v", req.CPUCount, node.CPUCount)
+		}
+		shares = append(shares, decimal.NewFromFloat(req.CPUCount).Div(decimal.NewFromFloat(node.CPUCount)))
+	}
+	if req.GPUCount > 0 {
+		if req.GPUCount > node.GPUCount {
+			return decimal.Zero, fmt.Errorf("requested %d GPUs but nodes have %d", req.GPUCount, node.GPUCount)
+		}
+		shares = append(shares, decimal.NewFromInt(int64(req.GPUCount)).Div(decimal.NewFromInt(int64(node.GPUCount))))
+	}
+	if req.Memory != nil && req.Memory.Sign() > 0 {
+		if node.Memory == nil || req.Memory.Cmp(*node.Memory) > 0 {
+			return decimal.Zero, fmt.Errorf("requested %v of memory but nodes have %v", req.Memory, node.Memory)
+		}
+		shares = append(shares, decimal.NewFromInt(req.Memory.Int64()).Div(decimal.NewFromInt(node.Memory.Int64())))
+	}
+
+	if len(shares) == 0 {
+		return one, nil
+	}
+	return decimal.Max(shares[0], shares[1:]...), nil
+}
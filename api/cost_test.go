@@ -0,0 +1,125 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/allenai/bytefmt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testClusters() []Cluster {
+	gpuCost := decimal.RequireFromString("12.24")
+	cpuCost := decimal.RequireFromString("0.10")
+	return []Cluster{
+		{
+			ID:       "c1",
+			Name:     "gpu",
+			FullName: "ai2/gpu",
+			NodeCost: &gpuCost,
+			NodeSpec: NodeResources{CPUCount: 32, GPUCount: 4, Memory: bytefmt.New(256<<30, bytefmt.Binary)},
+		},
+		{ID: "c2", Name: "cpu", FullName: "ai2/cpu", NodeCost: &cpuCost, NodeSpec: NodeResources{CPUCount: 8}},
+		{ID: "c3", Name: "onprem", FullName: "ai2/onprem"},
+	}
+}
+
+func TestTaskUsageCost(t *testing.T) {
+	jan := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	mar := feb.AddDate(0, 1, 0)
+	report := TaskUsageReport{
+		Series: []TaskUsageSeries{
+			{
+				Cluster: "ai2/gpu",
+				Author:  "alice",
+				Intervals: []UsageInterval{
+					{Start: jan, End: feb, Value: 10},
+					{Start: feb, End: mar, Value: 0.1},
+				},
+			},
+			{
+				Cluster:   "c1",
+				Author:    "bob",
+				Intervals: []UsageInterval{{Start: feb, End: mar, Value: 3}},
+			},
+			{Cluster: "ai2/onprem", Author: "bob", Totals: UsageInterval{Value: 100}},
+		},
+	}
+
+	calc := NewCostCalculator(testClusters())
+	cost, err := calc.TaskUsageCost(&report, TaskMetricGPUHours)
+	require.NoError(t, err)
+
+	// GPU hours cost 12.24 / 4 = 3.06.
+	assert.Equal(t, "40.086", cost.Total.String())
+	assert.Equal(t, "30.906", cost.ByAuthor["alice"].String())
+	assert.Equal(t, "9.18", cost.ByAuthor["bob"].String())
+	assert.Equal(t, "30.906", cost.ByCluster["ai2/gpu"].String())
+	assert.Equal(t, "9.18", cost.ByCluster["c1"].String())
+	assert.Empty(t, cost.ByWorkspace)
+	require.Len(t, cost.Intervals, 2)
+	assert.Equal(t, jan, cost.Intervals[0].Start)
+	assert.Equal(t, "30.6", cost.Intervals[0].Cost.String())
+	assert.Equal(t, "9.486", cost.Intervals[1].Cost.String())
+	assert.Equal(t, []string{"ai2/onprem"}, cost.Unpriced)
+
+	_, err = calc.TaskUsageCost(&TaskUsageReport{Series: []TaskUsageSeries{{Author: "alice"}}}, TaskMetricHours)
+	assert.Error(t, err)
+	_, err = calc.TaskUsageCost(&report, "memoryHours")
+	assert.Error(t, err)
+}
+
+func TestNodeUsageCost(t *testing.T) {
+	report := NodeUsageReport{
+		Series: []NodeUsageSeries{
+			{Cluster: "cpu", Totals: UsageInterval{Value: 24}},
+			{Cluster: "gpu", Totals: UsageInterval{Value: 2}},
+		},
+	}
+
+	cost, err := NewCostCalculator(testClusters()).NodeUsageCost(&report, NodeMetricHours)
+	require.NoError(t, err)
+	assert.Equal(t, "26.88", cost.Total.String())
+	assert.Equal(t, "2.4", cost.ByCluster["cpu"].String())
+	assert.Empty(t, cost.Unpriced)
+}
+
+func TestEstimateSpec(t *testing.T) {
+	calc := NewCostCalculator(testClusters())
+
+	cases := map[string]struct {
+		Resources *ResourceRequest
+		Cluster   string
+		Share     string
+		Cost      string
+		Error     bool
+	}{
+		"NoRequests": {Cluster: "ai2/gpu", Share: "1", Cost: "18.36"},
+		"GPUs":       {Resources: &ResourceRequest{CPUCount: 4, GPUCount: 1}, Cluster: "ai2/gpu", Share: "0.25", Cost: "4.59"},
+		"CPUs":       {Resources: &ResourceRequest{CPUCount: 16}, Cluster: "ai2/gpu", Share: "0.5", Cost: "9.18"},
+		"Memory":     {Resources: &ResourceRequest{Memory: bytefmt.New(192<<30, bytefmt.Binary)}, Cluster: "c1", Share: "0.75", Cost: "13.77"},
+		"TooLarge":   {Resources: &ResourceRequest{GPUCount: 1}, Cluster: "ai2/cpu", Error: true},
+		"NoCost":     {Cluster: "ai2/onprem", Error: true},
+		"Unknown":    {Cluster: "ai2/missing", Error: true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			spec := ExperimentSpecV2{Tasks: []TaskSpecV2{
+				{Name: "main", Resources: c.Resources, Context: Context{Cluster: c.Cluster}},
+			}}
+			cost, err := calc.EstimateSpec(&spec, 90*time.Minute)
+			if c.Error {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, cost.Tasks, 1)
+			assert.Equal(t, c.Share, cost.Tasks[0].Share.String())
+			assert.Equal(t, c.Cost, cost.Tasks[0].Cost.String())
+			assert.Equal(t, c.Cost, cost.Total.String())
+		})
+	}
+}
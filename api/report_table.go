@@ -0,0 +1,276 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// UsageRowKind distinguishes the rows of a usage table.
+type UsageRowKind string
+
+// Rows of a usage table are one of these kinds.
+const (
+	// UsageRowInterval is a series' value during a single interval.
+	UsageRowInterval UsageRowKind = "interval"
+
+	// UsageRowSeriesTotal is a series' value over the whole report.
+	UsageRowSeriesTotal UsageRowKind = "seriesTotal"
+
+	// UsageRowTotal is the value of every series over the whole report.
+	UsageRowTotal UsageRowKind = "total"
+)
+
+// UsageTable is a usage report in long format, with one row for each interval
+// of each series followed by the series' total, and a final row for the
+// report's total.
+type UsageTable struct {
+	// Columns names the dimensions by which the report may be grouped. Every
+	// column is present whether or not the report was grouped by it.
+	Columns []string
+
+	Rows []UsageRow
+}
+
+// UsageRow is a single value within a usage table.
+type UsageRow struct {
+	Kind  UsageRowKind
+	Start time.Time
+	End   time.Time
+
+	// Labels are the series' value for each of the table's columns. Labels
+	// are empty for columns by which the report wasn't grouped.
+	Labels []string
+
+	Value float64
+}
+
+var (
+	nodeUsageColumns = []string{
+		string(NodeGroupByNode),
+		string(NodeGroupByCluster),
+		string(NodeGroupByPreemptible),
+		string(NodeGroupByAutoscale),
+		string(NodeGroupByGPUCount),
+		string(NodeGroupByGPUType),
+	}
+	taskUsageColumns = []string{
+		string(TaskGroupByTask),
+		string(TaskGroupByExperiment),
+		string(TaskGroupByWorkspace),
+		string(TaskGroupByNode),
+		string(TaskGroupByCluster),
+		string(TaskGroupByAuthor),
+		string(TaskGroupByOwner),
+		string(TaskGroupByTeam),
+		string(TaskGroupByAutoscale),
+		string(TaskGroupByGPUCount),
+		string(TaskGroupByGPUType),
+	}
+)
+
+// Table converts a node usage report to long format.
+func (r *NodeUsageReport) Table() *UsageTable {
+	t := &UsageTable{Columns: append([]string(nil), nodeUsageColumns...)}
+	for _, s := range r.Series {
+		labels := []string{
+			s.Node,
+			s.Cluster,
+			formatBool(s.Preemptible),
+			formatBool(s.Autoscale),
+			formatInt(s.GPUCount),
+			formatString(s.GPUType),
+		}
+		t.addSeries(labels, s.Intervals, s.Totals)
+	}
+	t.addTotal(r.Totals)
+	return t
+}
+
+// Table converts a task usage report to long format.
+func (r *TaskUsageReport) Table() *UsageTable {
+	t := &UsageTable{Columns: append([]string(nil), taskUsageColumns...)}
+	for _, s := range r.Series {
+		labels := []string{
+			s.Task,
+			s.Experiment,
+			s.Workspace,
+			s.Node,
+			s.Cluster,
+			s.Author,
+			s.Owner,
+			s.Team,
+			formatBool(s.Autoscale),
+			formatInt(s.GPUCount),
+			formatString(s.GPUType),
+		}
+		t.addSeries(labels, s.Intervals, s.Totals)
+	}
+	t.addTotal(r.Totals)
+	return t
+}
+
+func (t *UsageTable) addSeries(labels []string, intervals []UsageInterval, totals UsageInterval) {
+	for _, i := range intervals {
+		t.Rows = append(t.Rows, UsageRow{
+			Kind:   UsageRowInterval,
+			Start:  i.Start,
+			End:    i.End,
+			Labels: labels,
+			Value:  i.Value,
+		})
+	}
+	t.Rows = append(t.Rows, UsageRow{
+		Kind:   UsageRowSeriesTotal,
+		Start:  totals.Start,
+		End:    totals.End,
+		Labels: labels,
+		Value:  totals.Value,
+	})
+}
+
+func (t *UsageTable) addTotal(totals UsageInterval) {
+	t.Rows = append(t.Rows, UsageRow{
+		Kind:   UsageRowTotal,
+		Start:  totals.Start,
+		End:    totals.End,
+		Labels: make([]string, len(t.Columns)),
+		Value:  totals.Value,
+	})
+}
+
+// Header lists the name of each field in a row, in the order they're written.
+func (t *UsageTable) Header() []string {
+	header := []string{"kind", "start", "end"}
+	header = append(header, t.Columns...)
+	return append(header, "value")
+}
+
+// WriteCSV writes the table as CSV with a header row. Times are formatted as
+// RFC 3339 and empty labels as empty strings.
+func (t *UsageTable) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Header()); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		record := []string{string(row.Kind), formatTime(row.Start), formatTime(row.End)}
+		record = append(record, row.Labels...)
+		record = append(record, strconv.FormatFloat(row.Value, 'f', -1, 64))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteNDJSON writes the table as newline-delimited JSON, with one object per
+// row. Fields are named and ordered as in Header. Labels of boolean and
+// numeric columns, such as preemptible and gpuCount, are written as JSON
+// booleans and numbers. Empty labels and times are written as null.
+func (t *UsageTable) WriteNDJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	header := t.Header()
+	var line bytes.Buffer
+	for _, row := range t.Rows {
+		fields := []interface{}{row.Kind, nullTime(row.Start), nullTime(row.End)}
+		for i, label := range row.Labels {
+			value, err := labelValue(t.Columns[i], label)
+			if err != nil {
+				return err
+			}
+			fields = append(fields, value)
+		}
+		fields = append(fields, row.Value)
+
+		line.Reset()
+		line.WriteByte('{')
+		for i, field := range fields {
+			if i != 0 {
+				line.WriteByte(',')
+			}
+			key, err := json.Marshal(header[i])
+			if err != nil {
+				return err
+			}
+			value, err := json.Marshal(field)
+			if err != nil {
+				return err
+			}
+			line.Write(key)
+			line.WriteByte(':')
+			line.Write(value)
+		}
+		line.WriteString("}\n")
+		if _, err := bw.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// labelValue converts a label to the JSON type of its column. Empty labels are nil.
+func labelValue(column, label string) (interface{}, error) {
+	if label == "" {
+		return nil, nil
+	}
+
+	// Task reports share the names of these columns.
+	switch column {
+	case string(NodeGroupByPreemptible), string(NodeGroupByAutoscale):
+		b, err := strconv.ParseBool(label)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column, err)
+		}
+		return b, nil
+	case string(NodeGroupByGPUCount):
+		i, err := strconv.Atoi(label)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column, err)
+		}
+		return i, nil
+	default:
+		return label, nil
+	}
+}
+
+func formatBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+func formatInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
+
+func formatString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeUsageTable(t *testing.T) {
+	jan := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	mar := feb.AddDate(0, 1, 0)
+	gpus := 8
+	report := NodeUsageReport{
+		Totals: UsageInterval{Start: jan, End: mar, Value: 30},
+		Series: []NodeUsageSeries{
+			{
+				Cluster:     "ai2/gpu",
+				Preemptible: BoolPtr(true),
+				GPUCount:    &gpus,
+				Totals:      UsageInterval{Start: jan, End: mar, Value: 30},
+				Intervals: []UsageInterval{
+					{Start: jan, End: feb, Value: 10.5},
+					{Start: feb, End: mar, Value: 19.5},
+				},
+			},
+		},
+	}
+
+	table := report.Table()
+	require.Len(t, table.Rows, 4)
+	assert.Equal(t, []UsageRowKind{UsageRowInterval, UsageRowInterval, UsageRowSeriesTotal, UsageRowTotal},
+		[]UsageRowKind{table.Rows[0].Kind, table.Rows[1].Kind, table.Rows[2].Kind, table.Rows[3].Kind})
+
+	var csv bytes.Buffer
+	require.NoError(t, table.WriteCSV(&csv))
+	assert.Equal(t, `kind,start,end,node,cluster,preemptible,autoscale,gpuCount,gpuType,value
+interval,2021-01-01T00:00:00Z,2021-02-01T00:00:00Z,,ai2/gpu,true,,8,,10.5
+interval,2021-02-01T00:00:00Z,2021-03-01T00:00:00Z,,ai2/gpu,true,,8,,19.5
+seriesTotal,2021-01-01T00:00:00Z,2021-03-01T00:00:00Z,,ai2/gpu,true,,8,,30
+total,2021-01-01T00:00:00Z,2021-03-01T00:00:00Z,,,,,,,30
+`, csv.String())
+
+	var ndjson bytes.Buffer
+	require.NoError(t, table.WriteNDJSON(&ndjson))
+	lines := bytes.Split(bytes.TrimSuffix(ndjson.Bytes(), []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 4)
+	assert.Equal(t, `{"kind":"interval","start":"2021-01-01T00:00:00Z","end":"2021-02-01T00:00:00Z",`+
+		`"node":null,"cluster":"ai2/gpu","preemptible":true,"autoscale":null,"gpuCount":8,"gpuType":null,"value":10.5}`,
+		string(lines[0]))
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[2], &fields))
+	assert.Equal(t, map[string]interface{}{
+		"kind":        "seriesTotal",
+		"start":       "2021-01-01T00:00:00Z",
+		"end":         "2021-03-01T00:00:00Z",
+		"node":        nil,
+		"cluster":     "ai2/gpu",
+		"preemptible": true,
+		"autoscale":   nil,
+		"gpuCount":    8.0,
+		"gpuType":     nil,
+		"value":       30.0,
+	}, fields)
+}
+
+func TestTaskUsageTable(t *testing.T) {
+	report := TaskUsageReport{
+		Totals: UsageInterval{Value: 3},
+		Series: []TaskUsageSeries{
+			{Author: "alice", Totals: UsageInterval{Value: 1}},
+			{Author: "bob", Team: "nlp", Totals: UsageInterval{Value: 2}},
+		},
+	}
+
+	table := report.Table()
+	assert.Equal(t, []string{"kind", "start", "end", "task", "experiment", "workspace", "node",
+		"cluster", "author", "owner", "team", "autoscale", "gpuCount", "gpuType", "value"}, table.Header())
+	require.Len(t, table.Rows, 3)
+	assert.Equal(t, "bob", table.Rows[1].Labels[5])
+	assert.Equal(t, "nlp", table.Rows[1].Labels[7])
+	assert.Equal(t, 3.0, table.Rows[2].Value)
+
+	var ndjson bytes.Buffer
+	require.NoError(t, table.WriteNDJSON(&ndjson))
+	line, err := ndjson.ReadBytes('\n')
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(line, &fields))
+	assert.Equal(t, "alice", fields["author"])
+	assert.Nil(t, fields["team"])
+	assert.Nil(t, fields["gpuCount"])
+}
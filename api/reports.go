@@ -17,6 +17,59 @@ const (
 	IntervalYear    Interval = "year"
 )
 
+// NodeMetric is a quantity measured over the lifetime of nodes.
+type NodeMetric string
+
+// Node usage reports may measure these metrics.
+const (
+	NodeMetricHours    NodeMetric = "hours"
+	NodeMetricCPUHours NodeMetric = "cpuHours"
+	NodeMetricGPUHours NodeMetric = "gpuHours"
+)
+
+// NodeGroupBy is a dimension by which node usage may be grouped into series.
+type NodeGroupBy string
+
+// Node usage reports may be grouped by these dimensions, each of which
+// corresponds to a field of NodeUsageSeries.
+const (
+	NodeGroupByNode        NodeGroupBy = "node"
+	NodeGroupByCluster     NodeGroupBy = "cluster"
+	NodeGroupByPreemptible NodeGroupBy = "preemptible"
+	NodeGroupByAutoscale   NodeGroupBy = "autoscale"
+	NodeGroupByGPUCount    NodeGroupBy = "gpuCount"
+	NodeGroupByGPUType     NodeGroupBy = "gpuType"
+)
+
+// TaskMetric is a quantity measured over the lifetime of tasks.
+type TaskMetric string
+
+// Task usage reports may measure these metrics.
+const (
+	TaskMetricHours    TaskMetric = "hours"
+	TaskMetricCPUHours TaskMetric = "cpuHours"
+	TaskMetricGPUHours TaskMetric = "gpuHours"
+)
+
+// TaskGroupBy is a dimension by which task usage may be grouped into series.
+type TaskGroupBy string
+
+// Task usage reports may be grouped by these dimensions, each of which
+// corresponds to a field of TaskUsageSeries.
+const (
+	TaskGroupByTask       TaskGroupBy = "task"
+	TaskGroupByExperiment TaskGroupBy = "experiment"
+	TaskGroupByWorkspace  TaskGroupBy = "workspace"
+	TaskGroupByNode       TaskGroupBy = "node"
+	TaskGroupByCluster    TaskGroupBy = "cluster"
+	TaskGroupByAuthor     TaskGroupBy = "author"
+	TaskGroupByOwner      TaskGroupBy = "owner"
+	TaskGroupByTeam       TaskGroupBy = "team"
+	TaskGroupByAutoscale  TaskGroupBy = "autoscale"
+	TaskGroupByGPUCount   TaskGroupBy = "gpuCount"
+	TaskGroupByGPUType    TaskGroupBy = "gpuType"
+)
+
 // NodeUsageReport contains one series for each combination of values in the group by.
 type NodeUsageReport struct {
 	Totals UsageInterval     `json:"totals"`
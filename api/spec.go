@@ -0,0 +1,210 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecError describes a single invalid field within a specification.
+type SpecError struct {
+	// Field is the path to the invalid field, such as "tasks[0].result.path".
+	Field string
+
+	// Message describes why the field is invalid.
+	Message string
+}
+
+// Error implements the standard error interface.
+func (e SpecError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// SpecErrors collects all validation failures within a specification.
+type SpecErrors []SpecError
+
+// Error implements the standard error interface.
+func (e SpecErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no errors"
+	case 1:
+		return e[0].Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d errors in spec:", len(e))
+	for _, err := range e {
+		b.WriteString("\n\t")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+func (e *SpecErrors) add(field, format string, args ...interface{}) {
+	*e = append(*e, SpecError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// ParseExperimentSpec decodes a JSON or YAML experiment specification. Unknown
+// fields are rejected. The spec is not validated; see ExperimentSpecV2.Validate.
+func ParseExperimentSpec(data []byte) (*ExperimentSpecV2, error) {
+	var spec ExperimentSpecV2
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("invalid spec: %w", err)
+		}
+		return &spec, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	return &spec, nil
+}
+
+// Validate checks an experiment specification against the rules documented on
+// each field. If the spec is invalid, the returned error is a SpecErrors
+// describing every violation.
+func (s *ExperimentSpecV2) Validate() error {
+	var errs SpecErrors
+
+	if s.Version != "v2-alpha" {
+		errs.add("version", "must be \"v2-alpha\"")
+	}
+	if len(s.Tasks) == 0 {
+		errs.add("tasks", "at least one task is required")
+	}
+
+	names := map[string]int{}
+	for i, task := range s.Tasks {
+		field := fmt.Sprintf("tasks[%d]", i)
+		if task.Name != "" {
+			if j, ok := names[task.Name]; ok {
+				errs.add(field+".name", "%q is already used by tasks[%d]", task.Name, j)
+			} else {
+				names[task.Name] = i
+			}
+		}
+		task.validate(field, &errs)
+	}
+	s.taskGraph(&errs)
+
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func (t *TaskSpecV2) validate(field string, errs *SpecErrors) {
+	switch {
+	case t.Image.Beaker == "" && t.Image.Docker == "":
+		errs.add(field+".image", "one of beaker or docker is required")
+	case t.Image.Beaker != "" && t.Image.Docker != "":
+		errs.add(field+".image", "only one of beaker or docker may be set")
+	}
+
+	envVars := map[string]int{}
+	for i, env := range t.EnvVars {
+		f := fmt.Sprintf("%s.envVars[%d]", field, i)
+		if env.Name == "" {
+			errs.add(f+".name", "is required")
+		} else if j, ok := envVars[env.Name]; ok {
+			errs.add(f+".name", "%q is already defined by envVars[%d]", env.Name, j)
+		} else {
+			envVars[env.Name] = i
+		}
+
+		switch {
+		case env.Value == nil && env.Secret == "":
+			errs.add(f, "one of value or secret is required")
+		case env.Value != nil && env.Secret != "":
+			errs.add(f, "only one of value or secret may be set")
+		}
+	}
+
+	mounts := map[string]int{}
+	for i, mount := range t.Datasets {
+		f := fmt.Sprintf("%s.datasets[%d]", field, i)
+		switch {
+		case mount.MountPath == "":
+			errs.add(f+".mountPath", "is required")
+		case !path.IsAbs(mount.MountPath):
+			errs.add(f+".mountPath", "%q must be absolute", mount.MountPath)
+		default:
+			// Mount paths differing only in case are considered overlapping.
+			p := strings.ToLower(path.Clean(mount.MountPath))
+			for other, j := range mounts {
+				if pathContains(p, other) || pathContains(other, p) {
+					errs.add(f+".mountPath", "%q overlaps with datasets[%d]", mount.MountPath, j)
+					break
+				}
+			}
+			mounts[p] = i
+		}
+
+		switch n := mount.Source.count(); {
+		case n == 0:
+			errs.add(f+".source", "one of beaker, hostPath, result, url, or secret is required")
+		case n > 1:
+			errs.add(f+".source", "only one of beaker, hostPath, result, url, or secret may be set")
+		}
+	}
+
+	if t.Result.Path == "" {
+		errs.add(field+".result.path", "is required")
+	}
+
+	if r := t.Resources; r != nil {
+		if r.CPUCount < 0 {
+			errs.add(field+".resources.cpuCount", "must not be negative")
+		}
+		if r.GPUCount < 0 {
+			errs.add(field+".resources.gpuCount", "must not be negative")
+		}
+		if r.Memory != nil && r.Memory.Sign() < 0 {
+			errs.add(field+".resources.memory", "must not be negative")
+		}
+	}
+
+	if t.Context.Cluster == "" {
+		errs.add(field+".context.cluster", "is required")
+	}
+	switch t.Context.Priority {
+	case "", LowPriority, NormalPriority, HighPriority:
+	case UrgentPriority:
+		errs.add(field+".context.priority", "%q may only be set after creation", t.Context.Priority)
+	default:
+		errs.add(field+".context.priority", "%q must be one of \"low\", \"normal\", or \"high\"", t.Context.Priority)
+	}
+}
+
+// count returns the number of sources defined.
+func (s DataSource) count() int {
+	n := 0
+	for _, v := range []string{s.Beaker, s.HostPath, s.Result, s.URL, s.Secret} {
+		if v != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// pathContains returns whether child is equal to or within parent. Both paths
+// must be clean and absolute.
+func pathContains(parent, child string) bool {
+	if parent == child || parent == "/" {
+		return true
+	}
+	return strings.HasPrefix(child, parent+"/")
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/allenai/bytefmt"
+)
+
+// ToV2 converts a v1 experiment spec to its v2 equivalent. Constructs which
+// can't be expressed in v2 are omitted from the result and described by the
+// returned errors, which are empty if the conversion is exact. Errors refer to
+// fields by their path within the v1 spec.
+//
+// The converted spec is not validated; see ExperimentSpecV2.Validate.
+func (s *ExperimentSpecV1) ToV2() (*ExperimentSpecV2, SpecErrors) {
+	var errs SpecErrors
+
+	if s.Version != "" && s.Version != "v1" {
+		errs.add("version", "%q is not a v1 spec", s.Version)
+	}
+	if s.Workspace != "" {
+		errs.add("workspace", "v2 specs have no workspace; set it when creating the experiment")
+	}
+	if s.AuthorToken != "" {
+		errs.add("authorToken", "v2 specs have no author token")
+	}
+
+	spec := &ExperimentSpecV2{
+		Version:     "v2-alpha",
+		Description: s.Description,
+		Tasks:       make([]TaskSpecV2, len(s.Tasks)),
+	}
+	for i, task := range s.Tasks {
+		spec.Tasks[i] = task.toV2(fmt.Sprintf("tasks[%d]", i), &errs)
+	}
+	return spec, errs
+}
+
+func (t *ExperimentTaskSpec) toV2(field string, errs *SpecErrors) TaskSpecV2 {
+	v1 := t.Spec
+	task := TaskSpecV2{
+		Name:      t.Name,
+		Command:   v1.Command,
+		Arguments: v1.Arguments,
+		Result:    ResultSpec{Path: v1.ResultPath},
+		Context:   Context{Cluster: t.Cluster},
+	}
+
+	switch {
+	case v1.Image != "" && v1.DockerImage != "":
+		errs.add(field+".spec.dockerImage", "only one image may be set in v2; using image %q", v1.Image)
+		task.Image.Beaker = v1.Image
+	case v1.Image != "":
+		task.Image.Beaker = v1.Image
+	case v1.DockerImage != "":
+		task.Image.Docker = v1.DockerImage
+	}
+
+	if v1.Description != "" {
+		errs.add(field+".spec.desc", "v2 tasks have no description")
+	}
+
+	// Maps are unordered, so sort variables for stable output.
+	names := make([]string, 0, len(v1.Env))
+	for name := range v1.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := v1.Env[name]
+		task.EnvVars = append(task.EnvVars, EnvironmentVariable{Name: name, Value: &value})
+	}
+
+	for _, mount := range v1.Mounts {
+		task.Datasets = append(task.Datasets, DataMount{
+			MountPath: mount.ContainerPath,
+			SubPath:   mount.SubPath,
+			Source:    DataSource{Beaker: mount.Dataset},
+		})
+	}
+
+	// Dependencies are mounted after the task's own mounts, as in v1.
+	for i, dep := range t.DependsOn {
+		if dep.ContainerPath == "" {
+			errs.add(fmt.Sprintf("%s.dependsOn[%d]", field, i),
+				"order-only dependency on %q can't be expressed in v2", dep.ParentName)
+			continue
+		}
+		task.Datasets = append(task.Datasets, DataMount{
+			MountPath: dep.ContainerPath,
+			Source:    DataSource{Result: dep.ParentName},
+		})
+	}
+
+	task.Resources = v1.Requirements.toV2(field+".spec.requirements", errs)
+
+	if t.Cluster == "" {
+		errs.add(field+".cluster", "v2 tasks require a cluster")
+	}
+	return task
+}
+
+func (r *TaskRequirements) toV2(field string, errs *SpecErrors) *ResourceRequest {
+	var req ResourceRequest
+
+	// The floating point and human-readable forms are only set when decoded
+	// from YAML, so they take precedence.
+	if r.CPU != 0 {
+		req.CPUCount = r.CPU
+	} else if r.MilliCPU != 0 {
+		req.CPUCount = float64(r.MilliCPU) / 1000
+	}
+
+	if r.MemoryHuman != "" {
+		memory, err := parseV1Memory(r.MemoryHuman)
+		if err != nil {
+			errs.add(field+".memory", "%v", err)
+		} else {
+			req.Memory = memory
+		}
+	} else if r.Memory != 0 {
+		req.Memory = bytefmt.New(r.Memory, bytefmt.Binary)
+	}
+
+	req.GPUCount = r.GPUCount
+
+	if req == (ResourceRequest{}) {
+		return nil
+	}
+	return &req
+}
+
+// parseV1Memory parses a v1 memory requirement. Like Docker, v1 treats
+// abbreviated unit suffixes such as "g" and "mb" as binary, whereas v2 treats
+// them as decimal, so they are rewritten to their explicit binary forms.
+func parseV1Memory(s string) (*bytefmt.Size, error) {
+	value := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "b")
+	if n := len(value); n != 0 && strings.IndexByte("kmgtp", value[n-1]) >= 0 {
+		return bytefmt.Parse(value[:n-1] + strings.ToUpper(value[n-1:]) + "iB")
+	}
+	return bytefmt.Parse(s)
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskGraph describes the dependencies between an experiment's tasks. A task
+// depends on each task whose result it mounts. Tasks are identified by their
+// index in the spec.
+type TaskGraph struct {
+	names    map[string]int
+	parents  [][]int
+	children [][]int
+	order    []int
+	depth    []int
+}
+
+// TaskGraph builds the dependency graph of a spec's tasks. It fails with
+// SpecErrors if a task mounts the result of a task which doesn't exist or if
+// dependencies form a cycle.
+func (s *ExperimentSpecV2) TaskGraph() (*TaskGraph, error) {
+	var errs SpecErrors
+	g := s.taskGraph(&errs)
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return g, nil
+}
+
+// taskGraph builds a task graph, adding errors to errs. The graph is only
+// complete if no errors are added.
+func (s *ExperimentSpecV2) taskGraph(errs *SpecErrors) *TaskGraph {
+	n := len(s.Tasks)
+	g := &TaskGraph{
+		names:    map[string]int{},
+		parents:  make([][]int, n),
+		children: make([][]int, n),
+		depth:    make([]int, n),
+	}
+	for i, task := range s.Tasks {
+		if _, ok := g.names[task.Name]; task.Name != "" && !ok {
+			g.names[task.Name] = i
+		}
+	}
+
+	for i, task := range s.Tasks {
+		for j, mount := range task.Datasets {
+			parent := mount.Source.Result
+			if parent == "" {
+				continue
+			}
+			p, ok := g.names[parent]
+			if !ok {
+				errs.add(fmt.Sprintf("tasks[%d].datasets[%d].source.result", i, j), "task %q not found", parent)
+				continue
+			}
+			if !containsInt(g.parents[i], p) {
+				g.parents[i] = append(g.parents[i], p)
+				g.children[p] = append(g.children[p], i)
+			}
+		}
+	}
+
+	// Order tasks with Kahn's algorithm, preferring tasks earlier in the spec.
+	pending := make([]int, n)
+	for i := range s.Tasks {
+		pending[i] = len(g.parents[i])
+	}
+	done := make([]bool, n)
+	for len(g.order) < n {
+		next := -1
+		for i := range s.Tasks {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			errs.add("tasks", "dependency cycle: %s", s.describeCycle(g, done))
+			return g
+		}
+
+		done[next] = true
+		g.order = append(g.order, next)
+		for _, c := range g.children[next] {
+			pending[c]--
+			if d := g.depth[next] + 1; d > g.depth[c] {
+				g.depth[c] = d
+			}
+		}
+	}
+	return g
+}
+
+// describeCycle names the tasks in a cycle among the tasks not yet ordered,
+// such as "a -> b -> a".
+func (s *ExperimentSpecV2) describeCycle(g *TaskGraph, done []bool) string {
+	// Every remaining task has a remaining parent, so following parents from
+	// any of them must eventually revisit a task.
+	start := 0
+	for done[start] {
+		start++
+	}
+
+	visited := map[int]int{}
+	var path []int
+	for i := start; ; {
+		if at, ok := visited[i]; ok {
+			path = append(path[at:], i)
+			break
+		}
+		visited[i] = len(path)
+		path = append(path, i)
+		for _, p := range g.parents[i] {
+			if !done[p] {
+				i = p
+				break
+			}
+		}
+	}
+
+	// Parents were followed backwards, so reverse to show dependency order.
+	names := make([]string, len(path))
+	for i, t := range path {
+		names[len(path)-1-i] = s.Tasks[t].Name
+	}
+	return strings.Join(names, " -> ")
+}
+
+// Index returns the index of the task with the given name.
+func (g *TaskGraph) Index(name string) (int, bool) {
+	i, ok := g.names[name]
+	return i, ok
+}
+
+// Order lists tasks such that each follows all of its parents. Otherwise,
+// tasks keep their order in the spec.
+func (g *TaskGraph) Order() []int {
+	return append([]int(nil), g.order...)
+}
+
+// Parents lists the tasks whose results a task mounts.
+func (g *TaskGraph) Parents(task int) []int {
+	return append([]int(nil), g.parents[task]...)
+}
+
+// Children lists the tasks which mount a task's result.
+func (g *TaskGraph) Children(task int) []int {
+	return append([]int(nil), g.children[task]...)
+}
+
+// Depth is the length of the longest chain of dependencies leading to a task.
+// Tasks without parents have a depth of zero.
+func (g *TaskGraph) Depth(task int) int {
+	return g.depth[task]
+}
+
+// Blocked lists the tasks which can't run because they depend, directly or
+// indirectly, on any of the given failed tasks. Tasks are listed in order,
+// excluding the failed tasks themselves. It's an error to give an index
+// outside of the spec's tasks.
+func (g *TaskGraph) Blocked(failed ...int) ([]int, error) {
+	isFailed := make([]bool, len(g.order))
+	blocked := make([]bool, len(g.order))
+	for _, f := range failed {
+		if f < 0 || f >= len(g.order) {
+			return nil, fmt.Errorf("task index %d out of range [0, %d)", f, len(g.order))
+		}
+		isFailed[f] = true
+		for _, c := range g.children[f] {
+			blocked[c] = true
+		}
+	}
+
+	var result []int
+	for _, t := range g.order {
+		if !blocked[t] {
+			continue
+		}
+		if !isFailed[t] {
+			result = append(result, t)
+		}
+		for _, c := range g.children[t] {
+			blocked[c] = true
+		}
+	}
+	return result, nil
+}
+
+func containsInt(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dependent returns a task which mounts the results of the named parents.
+func dependent(name string, parents ...string) TaskSpecV2 {
+	task := TaskSpecV2{Name: name}
+	for _, p := range parents {
+		task.Datasets = append(task.Datasets, DataMount{
+			MountPath: "/" + p,
+			Source:    DataSource{Result: p},
+		})
+	}
+	return task
+}
+
+func TestTaskGraph(t *testing.T) {
+	spec := ExperimentSpecV2{Tasks: []TaskSpecV2{
+		dependent("eval", "train"),
+		dependent("train", "prep", "prep"),
+		dependent("prep"),
+		dependent("report", "eval", "prep"),
+		dependent("other"),
+	}}
+
+	g, err := spec.TaskGraph()
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 1, 0, 3, 4}, g.Order())
+	assert.Equal(t, []int{2}, g.Parents(1))
+	assert.Equal(t, []int{1, 3}, g.Children(2))
+	assert.Equal(t, 3, g.Depth(3))
+	assert.Equal(t, 0, g.Depth(4))
+
+	train, ok := g.Index("train")
+	require.True(t, ok)
+	blocked, err := g.Blocked(train)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 3}, blocked)
+	blocked, err = g.Blocked(2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, blocked)
+	blocked, err = g.Blocked(4)
+	require.NoError(t, err)
+	assert.Empty(t, blocked)
+
+	_, err = g.Blocked(1, 5)
+	assert.EqualError(t, err, "task index 5 out of range [0, 5)")
+	_, err = g.Blocked(-1)
+	assert.EqualError(t, err, "task index -1 out of range [0, 5)")
+}
+
+func TestTaskGraphErrors(t *testing.T) {
+	spec := ExperimentSpecV2{Tasks: []TaskSpecV2{
+		dependent("a", "c"),
+		dependent("b", "a", "missing"),
+		dependent("c", "b"),
+		dependent("d"),
+	}}
+
+	_, err := spec.TaskGraph()
+	require.Error(t, err)
+	assert.Equal(t, SpecErrors{
+		{Field: "tasks[1].datasets[1].source.result", Message: `task "missing" not found`},
+		{Field: "tasks", Message: "dependency cycle: a -> b -> c -> a"},
+	}, err)
+
+	// Validation reports graph errors with all others.
+	err = spec.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle")
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/allenai/bytefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExperimentSpec(t *testing.T) {
+	yamlSpec := `
+version: v2-alpha
+tasks:
+- name: train
+  image:
+    beaker: me/trainer
+  result:
+    path: /output
+  resources:
+    memory: 2.5 GiB
+  context:
+    cluster: ai2/cpu
+`
+	spec, err := ParseExperimentSpec([]byte(yamlSpec))
+	require.NoError(t, err)
+	require.Len(t, spec.Tasks, 1)
+	assert.Equal(t, "me/trainer", spec.Tasks[0].Image.Beaker)
+	assert.Equal(t, int64(2684354560), spec.Tasks[0].Resources.Memory.Int64())
+	assert.NoError(t, spec.Validate())
+
+	jsonSpec := `{"version": "v2-alpha", "tasks": [{"image": {"docker": "busybox"}, "result": {"path": "/out"}, "context": {"cluster": "c"}}]}`
+	spec, err = ParseExperimentSpec([]byte(jsonSpec))
+	require.NoError(t, err)
+	assert.Equal(t, "busybox", spec.Tasks[0].Image.Docker)
+	assert.NoError(t, spec.Validate())
+
+	_, err = ParseExperimentSpec([]byte("version: v2-alpha\ntasks:\n- imag: {beaker: x}\n"))
+	assert.Error(t, err)
+
+	_, err = ParseExperimentSpec([]byte(`{"version": "v2-alpha", "task": []}`))
+	assert.Error(t, err)
+}
+
+func TestValidateExperimentSpec(t *testing.T) {
+	value := "1"
+	spec := ExperimentSpecV2{
+		Version: "v2",
+		Tasks: []TaskSpecV2{
+			{
+				Name:  "a",
+				Image: ImageSource{Beaker: "x", Docker: "y"},
+				EnvVars: []EnvironmentVariable{
+					{Name: "A", Value: &value},
+					{Name: "A", Secret: "s"},
+					{Value: &value, Secret: "s"},
+				},
+				Datasets: []DataMount{
+					{MountPath: "/data", Source: DataSource{Beaker: "d"}},
+					{MountPath: "/Data/sub", Source: DataSource{Beaker: "d", URL: "s3://b"}},
+					{MountPath: "relative"},
+				},
+				Resources: &ResourceRequest{GPUCount: -1},
+				Context:   Context{Cluster: "c", Priority: UrgentPriority},
+			},
+			{
+				Name:   "a",
+				Image:  ImageSource{Docker: "busybox"},
+				Result: ResultSpec{Path: "/out"},
+			},
+		},
+	}
+
+	err := spec.Validate()
+	require.Error(t, err)
+
+	var fields []string
+	for _, e := range err.(SpecErrors) {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{
+		"version",
+		"tasks[0].image",
+		"tasks[0].envVars[1].name",
+		"tasks[0].envVars[2].name",
+		"tasks[0].envVars[2]",
+		"tasks[0].datasets[1].mountPath",
+		"tasks[0].datasets[1].source",
+		"tasks[0].datasets[2].mountPath",
+		"tasks[0].datasets[2].source",
+		"tasks[0].result.path",
+		"tasks[0].resources.gpuCount",
+		"tasks[0].context.priority",
+		"tasks[1].name",
+		"tasks[1].context.cluster",
+	}, fields)
+}
+
+func TestExperimentSpecV1ToV2(t *testing.T) {
+	v1 := ExperimentSpecV1{
+		Workspace:   "me/ws",
+		Description: "pipeline",
+		Tasks: []ExperimentTaskSpec{
+			{
+				Name:    "prep",
+				Cluster: "ai2/cpu",
+				Spec: TaskSpecV1{
+					DockerImage: "busybox",
+					ResultPath:  "/out",
+					Env:         map[string]string{"B": "2", "A": "1"},
+					Mounts:      []DatasetMount{{Dataset: "ds1", SubPath: "x", ContainerPath: "/data"}},
+					Requirements: TaskRequirements{
+						MilliCPU: 1500,
+						Memory:   1 << 30,
+					},
+				},
+			},
+			{
+				Name: "train",
+				Spec: TaskSpecV1{
+					Image:        "me/trainer",
+					DockerImage:  "busybox",
+					ResultPath:   "/out",
+					Requirements: TaskRequirements{MemoryHuman: "2g", GPUCount: 1},
+				},
+				DependsOn: []TaskDependency{
+					{ParentName: "prep", ContainerPath: "/prep"},
+					{ParentName: "prep"},
+				},
+			},
+		},
+	}
+
+	v2, errs := v1.ToV2()
+	a := "1"
+	b := "2"
+	assert.Equal(t, &ExperimentSpecV2{
+		Version:     "v2-alpha",
+		Description: "pipeline",
+		Tasks: []TaskSpecV2{
+			{
+				Name:    "prep",
+				Image:   ImageSource{Docker: "busybox"},
+				EnvVars: []EnvironmentVariable{{Name: "A", Value: &a}, {Name: "B", Value: &b}},
+				Datasets: []DataMount{
+					{MountPath: "/data", SubPath: "x", Source: DataSource{Beaker: "ds1"}},
+				},
+				Result: ResultSpec{Path: "/out"},
+				Resources: &ResourceRequest{
+					CPUCount: 1.5,
+					Memory:   bytefmt.New(1<<30, bytefmt.Binary),
+				},
+				Context: Context{Cluster: "ai2/cpu"},
+			},
+			{
+				Name:  "train",
+				Image: ImageSource{Beaker: "me/trainer"},
+				Datasets: []DataMount{
+					{MountPath: "/prep", Source: DataSource{Result: "prep"}},
+				},
+				Result: ResultSpec{Path: "/out"},
+				Resources: &ResourceRequest{
+					GPUCount: 1,
+					Memory:   bytefmt.New(2<<30, bytefmt.Binary),
+				},
+			},
+		},
+	}, v2)
+
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{
+		"workspace",
+		"tasks[1].spec.dockerImage",
+		"tasks[1].dependsOn[1]",
+		"tasks[1].cluster",
+	}, fields)
+}
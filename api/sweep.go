@@ -0,0 +1,238 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// Sweep describes a set of parameter values with which to expand a task into
+// many tasks, such as for a hyperparameter search.
+type Sweep struct {
+	// (required) Parameters are varied across tasks. Each combination of
+	// values is a point in the sweep's grid, with the last parameter varying
+	// fastest.
+	Parameters []SweepParameter `json:"parameters" yaml:"parameters"`
+
+	// (optional) Samples limits the sweep to a random sample of this many
+	// distinct points from the grid. If zero or at least the size of the grid,
+	// every point is used.
+	Samples int `json:"samples,omitempty" yaml:"samples,omitempty"`
+
+	// (optional) Seed determines which points are sampled. The same seed
+	// always selects the same points.
+	Seed int64 `json:"seed,omitempty" yaml:"seed,omitempty"`
+}
+
+// SweepParameter describes the values of a single parameter. Each value is
+// passed to a task as an environment variable, an argument, or both.
+type SweepParameter struct {
+	// (optional) EnvVar is the name of an environment variable to set to the
+	// parameter's value, replacing any variable of the same name.
+	EnvVar string `json:"envVar,omitempty" yaml:"envVar,omitempty"`
+
+	// (optional) Flag is appended to a task's arguments with the parameter's
+	// value in the form "<flag>=<value>", such as "--lr=0.1".
+	Flag string `json:"flag,omitempty" yaml:"flag,omitempty"`
+
+	// (required) Values lists each value to try.
+	Values []string `json:"values" yaml:"values,flow"`
+}
+
+// Validate checks that a sweep is well formed. Errors are of type SpecErrors.
+func (s *Sweep) Validate() error {
+	var errs SpecErrors
+	if len(s.Parameters) == 0 {
+		errs.add("parameters", "at least one parameter is required")
+	}
+	envVars := map[string]bool{}
+	for i, p := range s.Parameters {
+		field := fmt.Sprintf("parameters[%d]", i)
+		if p.EnvVar == "" && p.Flag == "" {
+			errs.add(field, "either envVar or flag is required")
+		}
+		if p.EnvVar != "" {
+			if envVars[p.EnvVar] {
+				errs.add(field+".envVar", "duplicate environment variable %q", p.EnvVar)
+			}
+			envVars[p.EnvVar] = true
+		}
+		if len(p.Values) == 0 {
+			errs.add(field+".values", "at least one value is required")
+		}
+	}
+	if s.Samples < 0 {
+		errs.add("samples", "must not be negative")
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// Points returns the grid index of each point in the sweep, in ascending order.
+func (s *Sweep) Points() ([]int, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	size := 1
+	for _, p := range s.Parameters {
+		if size > math.MaxInt32/len(p.Values) {
+			return nil, errors.New("sweep has too many points")
+		}
+		size *= len(p.Values)
+	}
+
+	if s.Samples == 0 || s.Samples >= size {
+		points := make([]int, size)
+		for i := range points {
+			points[i] = i
+		}
+		return points, nil
+	}
+
+	// Select distinct points with Floyd's algorithm, which takes time
+	// proportional to the sample size rather than the grid size.
+	random := rand.New(rand.NewSource(s.Seed))
+	chosen := make(map[int]bool, s.Samples)
+	for j := size - s.Samples; j < size; j++ {
+		if t := random.Intn(j + 1); chosen[t] {
+			chosen[j] = true
+		} else {
+			chosen[t] = true
+		}
+	}
+
+	points := make([]int, 0, len(chosen))
+	for i := range chosen {
+		points = append(points, i)
+	}
+	sort.Ints(points)
+	return points, nil
+}
+
+// Values returns the value of each parameter at a point in the grid.
+func (s *Sweep) Values(point int) []string {
+	values := make([]string, len(s.Parameters))
+	for i := len(s.Parameters) - 1; i >= 0; i-- {
+		n := len(s.Parameters[i].Values)
+		values[i] = s.Parameters[i].Values[point%n]
+		point /= n
+	}
+	return values
+}
+
+// ExpandTask returns a copy of a task for each point in the sweep. Copies are
+// named after the base task and their point, such as "train-07".
+func (s *Sweep) ExpandTask(base TaskSpecV2) ([]TaskSpecV2, error) {
+	specs, err := s.ExpandSpec(ExperimentSpecV2{Version: "v2-alpha", Tasks: []TaskSpecV2{base}}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return specs[0].Tasks, nil
+}
+
+// ExpandSpec returns copies of a spec's tasks for each point in the sweep.
+// Result sources which refer to other tasks in the base spec are rewritten to
+// refer to the copy for the same point.
+//
+// If maxTasks is positive, the tasks are split across as many specs as needed
+// to hold at most maxTasks each. The tasks for a point are never split.
+func (s *Sweep) ExpandSpec(base ExperimentSpecV2, maxTasks int) ([]ExperimentSpecV2, error) {
+	if len(base.Tasks) == 0 {
+		return nil, errors.New("spec must have at least one task")
+	}
+	if maxTasks > 0 && maxTasks < len(base.Tasks) {
+		return nil, fmt.Errorf("spec has %d tasks, more than the maximum of %d", len(base.Tasks), maxTasks)
+	}
+
+	points, err := s.Points()
+	if err != nil {
+		return nil, err
+	}
+	perSpec := len(points)
+	if maxTasks > 0 {
+		perSpec = maxTasks / len(base.Tasks)
+	}
+
+	baseNames := make([]string, len(base.Tasks))
+	for i, task := range base.Tasks {
+		switch {
+		case task.Name != "":
+			baseNames[i] = task.Name
+		case len(base.Tasks) == 1:
+			baseNames[i] = "task"
+		default:
+			baseNames[i] = "task" + strconv.Itoa(i)
+		}
+	}
+
+	// Pad indices so names sort in grid order.
+	width := len(strconv.Itoa(points[len(points)-1]))
+
+	var specs []ExperimentSpecV2
+	for i, point := range points {
+		if i%perSpec == 0 {
+			specs = append(specs, ExperimentSpecV2{Version: base.Version, Description: base.Description})
+		}
+		spec := &specs[len(specs)-1]
+
+		names := make(map[string]string, len(base.Tasks))
+		for j, task := range base.Tasks {
+			if task.Name != "" {
+				names[task.Name] = fmt.Sprintf("%s-%0*d", baseNames[j], width, point)
+			}
+		}
+
+		values := s.Values(point)
+		for j, task := range base.Tasks {
+			task = task.clone()
+			task.Name = fmt.Sprintf("%s-%0*d", baseNames[j], width, point)
+			for k, mount := range task.Datasets {
+				if name, ok := names[mount.Source.Result]; ok {
+					task.Datasets[k].Source.Result = name
+				}
+			}
+			for k, p := range s.Parameters {
+				task.setParameter(p, values[k])
+			}
+			spec.Tasks = append(spec.Tasks, task)
+		}
+	}
+	return specs, nil
+}
+
+// setParameter passes a parameter's value to a task.
+func (t *TaskSpecV2) setParameter(p SweepParameter, value string) {
+	if p.EnvVar != "" {
+		envVars := t.EnvVars[:0]
+		for _, env := range t.EnvVars {
+			if env.Name != p.EnvVar {
+				envVars = append(envVars, env)
+			}
+		}
+		value := value
+		t.EnvVars = append(envVars, EnvironmentVariable{Name: p.EnvVar, Value: &value})
+	}
+	if p.Flag != "" {
+		t.Arguments = append(t.Arguments, p.Flag+"="+value)
+	}
+}
+
+// clone returns a copy of a task which shares no mutable state with the original.
+func (t TaskSpecV2) clone() TaskSpecV2 {
+	c := t
+	c.Command = append([]string(nil), t.Command...)
+	c.Arguments = append([]string(nil), t.Arguments...)
+	c.EnvVars = append([]EnvironmentVariable(nil), t.EnvVars...)
+	c.Datasets = append([]DataMount(nil), t.Datasets...)
+	if t.Resources != nil {
+		resources := *t.Resources
+		c.Resources = &resources
+	}
+	return c
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepExpandSpec(t *testing.T) {
+	value := "x"
+	base := ExperimentSpecV2{
+		Version: "v2-alpha",
+		Tasks: []TaskSpecV2{
+			{
+				Name:      "prep",
+				Arguments: []string{"prep.py"},
+				EnvVars:   []EnvironmentVariable{{Name: "LR", Value: &value}},
+			},
+			{
+				Name:     "train",
+				Datasets: []DataMount{{MountPath: "/prep", Source: DataSource{Result: "prep"}}},
+			},
+		},
+	}
+	sweep := Sweep{Parameters: []SweepParameter{
+		{EnvVar: "LR", Values: []string{"0.1", "0.01"}},
+		{Flag: "--batch", Values: []string{"16", "32", "64"}},
+	}}
+
+	specs, err := sweep.ExpandSpec(base, 4)
+	require.NoError(t, err)
+	require.Len(t, specs, 3)
+
+	var names []string
+	for _, spec := range specs {
+		for _, task := range spec.Tasks {
+			names = append(names, task.Name)
+		}
+	}
+	assert.Equal(t, []string{
+		"prep-0", "train-0", "prep-1", "train-1",
+		"prep-2", "train-2", "prep-3", "train-3",
+		"prep-4", "train-4", "prep-5", "train-5",
+	}, names)
+
+	// Point 4 is the second learning rate and the second batch size.
+	prep := specs[2].Tasks[0]
+	assert.Equal(t, []string{"prep.py", "--batch=32"}, prep.Arguments)
+	require.Len(t, prep.EnvVars, 1)
+	assert.Equal(t, "0.01", *prep.EnvVars[0].Value)
+	assert.Equal(t, "prep-4", specs[2].Tasks[1].Datasets[0].Source.Result)
+
+	// The base spec is unchanged.
+	assert.Equal(t, []string{"prep.py"}, base.Tasks[0].Arguments)
+	assert.Equal(t, "x", *base.Tasks[0].EnvVars[0].Value)
+	assert.Equal(t, "prep", base.Tasks[1].Datasets[0].Source.Result)
+
+	_, err = sweep.ExpandSpec(base, 1)
+	assert.Error(t, err)
+}
+
+func TestSweepSample(t *testing.T) {
+	sweep := Sweep{
+		Parameters: []SweepParameter{
+			{EnvVar: "A", Values: []string{"1", "2", "3", "4", "5"}},
+			{EnvVar: "B", Values: []string{"1", "2", "3", "4", "5"}},
+		},
+		Samples: 4,
+		Seed:    7,
+	}
+
+	points, err := sweep.Points()
+	require.NoError(t, err)
+	assert.Len(t, points, 4)
+	for i := 1; i < len(points); i++ {
+		assert.Less(t, points[i-1], points[i])
+	}
+
+	again, err := sweep.Points()
+	require.NoError(t, err)
+	assert.Equal(t, points, again)
+
+	tasks, err := sweep.ExpandTask(TaskSpecV2{Name: "t"})
+	require.NoError(t, err)
+	require.Len(t, tasks, 4)
+	assert.Equal(t, sweep.Values(points[0])[1], *tasks[0].EnvVars[1].Value)
+
+	err = (&Sweep{Parameters: []SweepParameter{{Values: []string{"1"}}, {EnvVar: "A"}}}).Validate()
+	require.Error(t, err)
+	assert.Len(t, err.(SpecErrors), 2)
+}
@@ -11,6 +11,8 @@ import (
 
 // WhoAmI returns a client's active user.
 func (c *Client) WhoAmI(ctx context.Context) (*api.UserDetail, error) {
+	ctx = withOperation(ctx, "Client.WhoAmI")
+
 	uri := path.Join("/api/v3/user")
 	resp, err := c.sendRetryableRequest(ctx, http.MethodGet, uri, nil, nil)
 	if err != nil {
@@ -32,6 +34,8 @@ func (c *Client) WhoAmI(ctx context.Context) (*api.UserDetail, error) {
 
 // GenerateToken creates a new token for authentication.
 func (c *Client) GenerateToken(ctx context.Context) (string, error) {
+	ctx = withOperation(ctx, "Client.GenerateToken")
+
 	resp, err := c.sendRetryableRequest(ctx, http.MethodPost, "/api/v3/auth/tokens", nil, nil)
 	if err != nil {
 		return "", err
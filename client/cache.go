@@ -0,0 +1,178 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// WithCache caches the results of Get and Resolve on workspace, dataset,
+// experiment, image, group, and cluster handles for the given duration.
+// Cached objects may be stale by up to ttl unless modified through the same
+// client, which invalidates them. Each call returns its own copy of a cached
+// object, which the caller may modify freely.
+//
+// Methods which must observe changes, such as ExperimentHandle.Wait, always
+// bypass the cache.
+func WithCache(ttl time.Duration) Option {
+	return optionFunc(func(c *Client) error {
+		if ttl <= 0 {
+			return errors.New("cache TTL must be positive")
+		}
+		c.cache = &cache{
+			ttl:     ttl,
+			now:     time.Now,
+			entries: map[string]*cacheEntry{},
+			names:   map[cacheKey]string{},
+		}
+		return nil
+	})
+}
+
+// Kinds of cached objects. Names are only unique within a kind.
+const (
+	workspaceKind  = "workspace"
+	datasetKind    = "dataset"
+	experimentKind = "experiment"
+	imageKind      = "image"
+	groupKind      = "group"
+	clusterKind    = "cluster"
+)
+
+// cache holds objects by ID and an index of names to IDs. A nil cache is
+// valid and caches nothing.
+//
+// Objects are stored encoded as JSON so that each caller receives its own deep
+// copy. Otherwise a caller which modified a slice or pointer in a returned
+// object, such as an experiment's executions, would modify the cache.
+type cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	names   map[cacheKey]string
+}
+
+type cacheKey struct {
+	kind string
+	name string
+}
+
+type cacheEntry struct {
+	kind    string
+	names   []string
+	value   []byte // JSON-encoded
+	expires time.Time
+}
+
+// get decodes the object to which a name or ID refers into value. It reports
+// whether the object was found.
+func (c *cache) get(kind, ref string, value interface{}) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := ref
+	if mapped, ok := c.names[cacheKey{kind, ref}]; ok {
+		id = mapped
+	}
+	entry, ok := c.entries[id]
+	if !ok || entry.kind != kind {
+		return false
+	}
+	if c.now().After(entry.expires) {
+		c.remove(id)
+		return false
+	}
+	return json.Unmarshal(entry.value, value) == nil
+}
+
+// put caches a copy of an object by its ID and each of its names.
+func (c *cache) put(kind, id string, value interface{}, names ...string) {
+	if c == nil {
+		return
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(id)
+	entry := &cacheEntry{kind: kind, value: encoded, expires: c.now().Add(c.ttl)}
+	for _, name := range names {
+		if name != "" && name != id {
+			entry.names = append(entry.names, name)
+			c.names[cacheKey{kind, name}] = id
+		}
+	}
+	c.entries[id] = entry
+}
+
+// invalidate removes an object referred to by name or ID, along with every
+// name by which it's cached.
+//
+// A ref may refer to a cached object by a form under which it wasn't cached,
+// such as a name relative to the user where the object was cached by its full
+// name. Unless a ref is an ID, or a name cached for an object, every object of
+// the kind is removed.
+func (c *cache) invalidate(kind, ref string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id, ok := c.names[cacheKey{kind, ref}]; ok {
+		c.remove(id)
+		return
+	}
+	if entry, ok := c.entries[ref]; ok && entry.kind == kind {
+		c.remove(ref)
+		return
+	}
+	if isID(ref) {
+		return
+	}
+	for id, entry := range c.entries {
+		if entry.kind == kind {
+			c.remove(id)
+		}
+	}
+}
+
+// invalidateIDs removes objects of any kind by their IDs.
+func (c *cache) invalidateIDs(ids ...string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		c.remove(id)
+	}
+}
+
+// remove deletes an entry and its names. The caller must hold c.mu.
+func (c *cache) remove(id string) {
+	entry, ok := c.entries[id]
+	if !ok {
+		return
+	}
+	for _, name := range entry.names {
+		if c.names[cacheKey{entry.kind, name}] == id {
+			delete(c.names, cacheKey{entry.kind, name})
+		}
+	}
+	delete(c.entries, id)
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beaker/client/api"
+)
+
+func TestCache(t *testing.T) {
+	const id = "01F8GQ3Y4ZKX7W9M2N5P6R0T1V"
+	var gets, patches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			gets++
+			_, _ = w.Write([]byte(`{"id": "` + id + `", "fullName": "me/data"}`))
+		case http.MethodPatch:
+			patches++
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "", WithCache(time.Minute))
+	require.NoError(t, err)
+	now := time.Now()
+	c.cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	handle, err := c.Dataset("me/data").Resolve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, id, handle.Ref())
+	assert.Equal(t, 1, gets)
+
+	// Both the name and ID are served from the cache.
+	dataset, err := c.Dataset("me/data").Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, id, dataset.ID)
+	_, err = handle.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, gets)
+
+	// Modifying the returned object doesn't affect the cache.
+	dataset.FullName = "modified"
+	dataset, err = handle.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "me/data", dataset.FullName)
+
+	// Changes through any handle invalidate the cache.
+	require.NoError(t, handle.SetName(ctx, "renamed"))
+	assert.Equal(t, 1, patches)
+	_, err = c.Dataset("me/data").Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, gets)
+
+	// Entries expire.
+	now = now.Add(time.Minute + time.Second)
+	_, err = handle.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, gets)
+
+	// Names are scoped by kind.
+	assert.False(t, c.cache.get(experimentKind, "me/data", &api.Experiment{}))
+}
+
+func TestCacheInvalidateRefs(t *testing.T) {
+	const id = "01F8GQ3Y4ZKX7W9M2N5P6R0T1V"
+	gets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets++
+			_, _ = w.Write([]byte(`{"id": "` + id + `", "fullName": "me/data"}`))
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "", WithCache(time.Minute))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	get := func(ref string) {
+		_, err := c.Dataset(ref).Get(ctx)
+		require.NoError(t, err)
+	}
+
+	// A name relative to the user invalidates the object cached by full name.
+	get("me/data")
+	get(id)
+	assert.Equal(t, 1, gets)
+	require.NoError(t, c.Dataset("data").SetName(ctx, "renamed"))
+	get("me/data")
+	get(id)
+	assert.Equal(t, 2, gets)
+
+	// An ID invalidates every name cached for the object.
+	require.NoError(t, c.Dataset(id).Delete(ctx))
+	get("me/data")
+	assert.Equal(t, 3, gets)
+
+	// A full name invalidates the object cached by ID.
+	require.NoError(t, c.Dataset("me/data").SetDescription(ctx, "description"))
+	get(id)
+	assert.Equal(t, 4, gets)
+
+	// Other kinds are unaffected.
+	_, err = c.Experiment(id).Get(ctx)
+	require.NoError(t, err)
+	require.NoError(t, c.Dataset("other").Delete(ctx))
+	_, err = c.Experiment(id).Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, gets)
+}
+
+func TestCacheDeepCopy(t *testing.T) {
+	gets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		_, _ = w.Write([]byte(`{"id": "x", "executions": [{"id": "e1", "state": {"exitCode": 0}}]}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "", WithCache(time.Minute))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	experiment, err := c.Experiment("x").Get(ctx)
+	require.NoError(t, err)
+
+	// Modifying slices and pointers within the returned object, whether from
+	// the service or the cache, doesn't affect later results.
+	for i := 0; i < 2; i++ {
+		require.Len(t, experiment.Executions, 1)
+		experiment.Executions[0].ID = "modified"
+		*experiment.Executions[0].State.ExitCode = 1
+		experiment.Executions = append(experiment.Executions, &api.Execution{})
+
+		experiment, err = c.Experiment("x").Get(ctx)
+		require.NoError(t, err)
+		require.Len(t, experiment.Executions, 1)
+		assert.Equal(t, "e1", experiment.Executions[0].ID)
+		assert.Equal(t, 0, *experiment.Executions[0].State.ExitCode)
+	}
+	assert.Equal(t, 1, gets)
+}
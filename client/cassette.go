@@ -0,0 +1,288 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/beaker/client/api"
+)
+
+// CassetteMode determines whether a cassette is recorded or replayed.
+type CassetteMode int
+
+const (
+	// RecordCassette sends requests to the service as usual and writes each
+	// exchange to the cassette, replacing any existing recording.
+	RecordCassette CassetteMode = iota
+
+	// ReplayCassette serves responses from the cassette without contacting
+	// the service.
+	ReplayCassette
+)
+
+// ErrNotRecorded is returned when replaying a request which doesn't match any
+// remaining exchange in a cassette.
+var ErrNotRecorded = errors.New("request not recorded in cassette")
+
+// scrubbedHeaders are credentials which are never written to a cassette.
+var scrubbedHeaders = []string{"Authorization", api.HeaderAuthor}
+
+const scrubbedValue = "REDACTED"
+
+// WithCassette records or replays every HTTP exchange made by the client
+// using a JSON cassette file at the given path. Credentials in request headers
+// are scrubbed from recordings, but bodies are recorded verbatim.
+//
+// When recording, requests are sent through the transport configured by any
+// preceding options, so this option should follow WithHTTPClient or
+// WithTransport. When replaying, each request is served the first unused
+// exchange with the same method, path, and query, in recorded order.
+func WithCassette(path string, mode CassetteMode) Option {
+	return optionFunc(func(c *Client) error {
+		switch mode {
+		case RecordCassette:
+			next := c.httpClient.Transport
+			if next == nil {
+				next = http.DefaultTransport
+			}
+			r := &cassetteRecorder{path: path, next: next}
+			if err := r.create(); err != nil {
+				return err
+			}
+			c.httpClient.Transport = r
+
+		case ReplayCassette:
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var cassette cassette
+			if err := json.Unmarshal(b, &cassette); err != nil {
+				return fmt.Errorf("invalid cassette %s: %w", path, err)
+			}
+			c.httpClient.Transport = &cassettePlayer{
+				path:     path,
+				cassette: cassette,
+				used:     make([]bool, len(cassette.Interactions)),
+			}
+
+		default:
+			return fmt.Errorf("invalid cassette mode %d", mode)
+		}
+		return nil
+	})
+}
+
+// cassette is the file format of a recording.
+type cassette struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	recordedBody
+}
+
+type recordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	recordedBody
+}
+
+// recordedBody holds a request or response body. Bodies which aren't valid
+// UTF-8 are encoded as base64.
+type recordedBody struct {
+	Body     string `json:"body,omitempty"`
+	Encoding string `json:"bodyEncoding,omitempty"`
+}
+
+func newRecordedBody(b []byte) recordedBody {
+	if utf8.Valid(b) {
+		return recordedBody{Body: string(b)}
+	}
+	return recordedBody{Body: base64.StdEncoding.EncodeToString(b), Encoding: "base64"}
+}
+
+func (b recordedBody) bytes() ([]byte, error) {
+	if b.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(b.Body)
+	}
+	return []byte(b.Body), nil
+}
+
+// Recordings are written incrementally. The file always holds a complete
+// cassette, and each exchange is written over the footer of the last.
+const (
+	cassetteHeader = "{\n  \"interactions\": ["
+	cassetteFooter = "\n  ]\n}\n"
+)
+
+// cassetteRecorder is a transport which records all exchanges to a file.
+type cassetteRecorder struct {
+	path string
+	next http.RoundTripper
+
+	mu     sync.Mutex
+	count  int   // Number of exchanges recorded.
+	offset int64 // Offset of the footer within the file.
+}
+
+func (r *cassetteRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		// Transports must not modify the request, so send a copy.
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	safeClose(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	header := req.Header.Clone()
+	for _, key := range scrubbedHeaders {
+		if header.Get(key) != "" {
+			header.Set(key, scrubbedValue)
+		}
+	}
+
+	exchange := interaction{
+		Request: recordedRequest{
+			Method:       req.Method,
+			URL:          req.URL.String(),
+			Header:       header,
+			recordedBody: newRecordedBody(reqBody),
+		},
+		Response: recordedResponse{
+			StatusCode:   resp.StatusCode,
+			Header:       resp.Header.Clone(),
+			recordedBody: newRecordedBody(respBody),
+		},
+	}
+	if err := r.append(exchange); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// create writes an empty cassette, replacing any existing file.
+func (r *cassetteRecorder) create() error {
+	r.offset = int64(len(cassetteHeader))
+	return ioutil.WriteFile(r.path, []byte(cassetteHeader+cassetteFooter), 0644)
+}
+
+// append adds an exchange to the end of the cassette.
+func (r *cassetteRecorder) append(exchange interaction) error {
+	b, err := json.MarshalIndent(exchange, "    ", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var buf bytes.Buffer
+	if r.count != 0 {
+		buf.WriteByte(',')
+	}
+	buf.WriteString("\n    ")
+	buf.Write(b)
+	n := buf.Len()
+	buf.WriteString(cassetteFooter)
+
+	f, err := os.OpenFile(r.path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(buf.Bytes(), r.offset); err != nil {
+		safeClose(f)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	r.count++
+	r.offset += int64(n)
+	return nil
+}
+
+// cassettePlayer is a transport which serves exchanges from a recording.
+type cassettePlayer struct {
+	path string
+
+	mu       sync.Mutex
+	cassette cassette
+	used     []bool
+}
+
+func (p *cassettePlayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, exchange := range p.cassette.Interactions {
+		if p.used[i] || exchange.Request.Method != req.Method {
+			continue
+		}
+		u, err := url.Parse(exchange.Request.URL)
+		if err != nil || u.Path != req.URL.Path || u.RawQuery != req.URL.RawQuery {
+			continue
+		}
+
+		body, err := exchange.Response.bytes()
+		if err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %w", p.path, err)
+		}
+		p.used[i] = true
+
+		code := exchange.Response.StatusCode
+		return &http.Response{
+			Status:        strconv.Itoa(code) + " " + http.StatusText(code),
+			StatusCode:    code,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        exchange.Response.Header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.RequestURI(), ErrNotRecorded)
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beaker/client/api"
+)
+
+func TestCassette(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/user":
+			_ = json.NewEncoder(w).Encode(api.UserDetail{Identity: api.Identity{ID: "u1", Name: "alice"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(api.Error{Code: http.StatusNotFound, Message: "not found"})
+		}
+	}))
+
+	dir, err := ioutil.TempDir("", "cassette")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	recorder, err := NewClient(server.URL, "secret-token", WithCassette(path, RecordCassette))
+	require.NoError(t, err)
+	user, err := recorder.WhoAmI(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user.Name)
+	_, err = recorder.Workspace("missing").Get(ctx)
+	assert.True(t, IsNotFound(err))
+	server.Close()
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.False(t, strings.Contains(string(b), "secret-token"))
+	assert.Contains(t, string(b), scrubbedValue)
+
+	player, err := NewClient(server.URL, "other-token", WithCassette(path, ReplayCassette))
+	require.NoError(t, err)
+	user, err = player.WhoAmI(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user.Name)
+	_, err = player.Workspace("missing").Get(ctx)
+	assert.True(t, IsNotFound(err))
+
+	// Each exchange is only replayed once.
+	_, err = player.WhoAmI(ctx)
+	assert.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestCassetteScrubsAuthor(t *testing.T) {
+	ctx := context.Background()
+	var author string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		author = r.Header.Get(api.HeaderAuthor)
+		_ = json.NewEncoder(w).Encode(api.Experiment{ID: "ex1"})
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "cassette")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	client, err := NewClient(server.URL, "secret-token", WithCassette(path, RecordCassette))
+	require.NoError(t, err)
+
+	spec := &api.ExperimentSpecV2{Version: "v2-alpha"}
+	for i := 0; i < 3; i++ {
+		_, err = client.Workspace("org/ws").CreateExperiment(ctx, spec, &ExperimentOpts{AuthorToken: "author-token"})
+		require.NoError(t, err)
+		assert.Equal(t, "author-token", author, "the service should receive the real token")
+
+		// The cassette is complete after each exchange.
+		b, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		var c cassette
+		require.NoError(t, json.Unmarshal(b, &c))
+		require.Len(t, c.Interactions, i+1)
+		for _, exchange := range c.Interactions {
+			assert.Equal(t, scrubbedValue, exchange.Request.Header.Get(api.HeaderAuthor))
+			assert.Equal(t, scrubbedValue, exchange.Request.Header.Get("Authorization"))
+		}
+		assert.NotContains(t, string(b), "author-token")
+		assert.NotContains(t, string(b), "secret-token")
+	}
+}
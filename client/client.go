@@ -11,15 +11,19 @@ import (
 	"log"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
+	"runtime"
 	"strings"
 	"time"
 
 	"github.com/goware/urlx"
 	retryable "github.com/hashicorp/go-retryablehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/beaker/client/api"
 )
@@ -38,9 +42,22 @@ type Client struct {
 	userToken string
 
 	userAgent string
+
+	defaultOrg       string
+	defaultWorkspace string
+
+	// httpClient is shared by all requests so connections may be reused.
+	httpClient  *http.Client
+	retryPolicy RetryPolicy
+	rateLimit   *RateLimit
+	cache       *cache
+	middleware  []Middleware
+	tracer      trace.Tracer
+	propagator  propagation.TextMapPropagator
+
 	// If set, then HTTPResponseHook will be invoked after every HTTP response
 	// arrives. This can be used by users of this client to implement diagnostics,
-	// such as request logging.
+	// such as request logging. See WithMiddleware for a more flexible alternative.
 	HTTPResponseHook HTTPResponseHook
 }
 
@@ -64,6 +81,55 @@ func WithUserAgent(userAgent string) Option {
 	})
 }
 
+// WithHTTPClient sets the HTTP client used to send all requests. The client is
+// copied and should not be modified after it's passed in. If the client has no
+// redirect policy, headers are copied to redirected requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return optionFunc(func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("HTTP client must not be nil")
+		}
+		hc := *httpClient
+		if hc.CheckRedirect == nil {
+			hc.CheckRedirect = copyRedirectHeader
+		}
+		c.httpClient = &hc
+		return nil
+	})
+}
+
+// WithTransport sets the transport used to send all requests. This can be used
+// to configure connection pooling, TLS, or proxies. See DefaultTransport for a
+// reasonable starting point.
+func WithTransport(transport http.RoundTripper) Option {
+	return optionFunc(func(c *Client) error {
+		if transport == nil {
+			return errors.New("transport must not be nil")
+		}
+		c.httpClient.Transport = transport
+		return nil
+	})
+}
+
+// DefaultTransport returns a new transport with the client's default settings.
+// Connections are pooled and kept alive between requests, and HTTP/2 is used
+// where the server supports it. Proxies are read from the environment.
+func DefaultTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   runtime.GOMAXPROCS(0) + 1,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
 // HTTPResponseHook will be given an HTTP response, and the duration that the request took.
 // When inspecting the response, don't read or close the response body, as that will affect
 // the client behavior.
@@ -71,18 +137,20 @@ type HTTPResponseHook func(resp *http.Response, duration time.Duration)
 
 // NewClient creates a new Beaker client bound to a single user.
 func NewClient(address string, userToken string, opts ...Option) (*Client, error) {
-	u, err := urlx.ParseWithDefaultScheme(address, "https")
+	u, err := parseAddress(address)
 	if err != nil {
 		return nil, err
 	}
 
-	if u.Path != "" || u.Opaque != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
-		return nil, errors.New("address must be base server address in the form [scheme://]host[:port]")
-	}
-
 	client := &Client{
 		baseURL:   *u,
 		userToken: userToken,
+		httpClient: &http.Client{
+			Transport:     DefaultTransport(),
+			Timeout:       30 * time.Second,
+			CheckRedirect: copyRedirectHeader,
+		},
+		retryPolicy: DefaultRetryPolicy(),
 	}
 
 	for _, opt := range opts {
@@ -91,6 +159,10 @@ func NewClient(address string, userToken string, opts ...Option) (*Client, error
 		}
 	}
 
+	client.applyRateLimit()
+	client.applyMiddleware()
+	client.applyTracing()
+
 	if client.userAgent == "" {
 		exec, err := os.Executable()
 		if err != nil {
@@ -102,24 +174,49 @@ func NewClient(address string, userToken string, opts ...Option) (*Client, error
 	return client, nil
 }
 
-func newRetryableClient(httpClient *http.Client, httpResponseHook HTTPResponseHook) *retryable.Client {
+// parseAddress parses a base server address in the form [scheme://]host[:port].
+func parseAddress(address string) (*url.URL, error) {
+	u, err := urlx.ParseWithDefaultScheme(address, "https")
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Path != "" || u.Opaque != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return nil, errors.New("address must be base server address in the form [scheme://]host[:port]")
+	}
+	return u, nil
+}
+
+// newRetryableClient creates a client to send a single request of the given
+// method. Retryable clients are cheap; each wraps the shared HTTP client.
+func (c *Client) newRetryableClient(method string) *retryable.Client {
+	policy := c.retryPolicy
 	rc := &retryable.Client{
-		HTTPClient:   httpClient,
+		HTTPClient:   c.httpClient,
 		Logger:       &errorLogger{Logger: log.New(os.Stderr, "", log.LstdFlags)},
-		RetryWaitMin: 100 * time.Millisecond,
-		RetryWaitMax: 30 * time.Second,
-		RetryMax:     9,
-		CheckRetry:   retryable.DefaultRetryPolicy,
-		Backoff:      exponentialJitterBackoff,
+		RetryWaitMin: policy.WaitMin,
+		RetryWaitMax: policy.WaitMax,
+		RetryMax:     policy.MaxRetries,
+		CheckRetry:   policy.checkRetry(method),
+		Backoff:      policy.backoff,
 		ErrorHandler: retryable.PassthroughErrorHandler,
 	}
 
-	if httpResponseHook != nil {
-		th := &timingHook{responseHook: httpResponseHook}
-		rc.RequestLogHook = th.RequestLogHook
+	var th *timingHook
+	if c.HTTPResponseHook != nil {
+		th = &timingHook{responseHook: c.HTTPResponseHook}
 		rc.ResponseLogHook = th.ResponseLogHook
 	}
 
+	rc.RequestLogHook = func(logger retryable.Logger, req *http.Request, attempt int) {
+		if n, ok := req.Context().Value(attemptKey{}).(*int); ok {
+			*n = attempt + 1
+		}
+		if th != nil {
+			th.RequestLogHook(logger, req, attempt)
+		}
+	}
+
 	return rc
 }
 
@@ -158,11 +255,19 @@ func (c *Client) sendRetryableRequest(
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	return c.do(ctx, req)
+}
+
+// do sends a request through the client's shared HTTP client, retrying on
+// transient failures.
+func (c *Client) do(ctx context.Context, req *retryable.Request) (*http.Response, error) {
+	attempt := 0
+	ctx = context.WithValue(ctx, attemptKey{}, &attempt)
 
-	return newRetryableClient(&http.Client{
-		Timeout:       30 * time.Second,
-		CheckRedirect: copyRedirectHeader,
-	}, c.HTTPResponseHook).Do(req.WithContext(ctx))
+	ctx, span := c.startOperation(ctx, req.Request)
+	resp, err := c.newRetryableClient(req.Method).Do(req.WithContext(ctx))
+	endSpan(span, resp, err)
+	return resp, err
 }
 
 func (c *Client) newRequest(
@@ -234,10 +339,19 @@ func errorFromResponse(resp *http.Response) error {
 	}
 
 	var apiErr api.Error
-	if err := json.Unmarshal(bytes, &apiErr); err != nil {
-		return fmt.Errorf("failed to parse response: %s", string(bytes))
+	if err := json.Unmarshal(bytes, &apiErr); err != nil || apiErr.Message == "" {
+		respErr := &ResponseError{StatusCode: resp.StatusCode, Body: string(bytes)}
+		if resp.Request != nil {
+			respErr.Method = resp.Request.Method
+			respErr.Path = resp.Request.URL.Path
+		}
+		return respErr
 	}
 
+	// Not all services set the code, but the response always has one.
+	if apiErr.Code == 0 {
+		apiErr.Code = resp.StatusCode
+	}
 	return apiErr
 }
 
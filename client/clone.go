@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beaker/client/api"
+)
+
+// TaskOverrides replaces parts of a task's spec. Empty fields are unchanged.
+type TaskOverrides struct {
+	// Image replaces the task's image.
+	Image *api.ImageSource
+
+	// Cluster replaces the cluster on which the task runs.
+	Cluster string
+
+	// Priority replaces the task's priority.
+	Priority api.Priority
+
+	// EnvVars are added to the task's environment variables, replacing any of
+	// the same name.
+	EnvVars []api.EnvironmentVariable
+
+	// Arguments replace the task's arguments.
+	Arguments []string
+}
+
+// CloneOverrides describes how a cloned experiment differs from the original.
+// All fields are optional.
+type CloneOverrides struct {
+	// Name of the new experiment. If empty, the name is derived from the
+	// original, such as "my-exp-clone" or "my-exp-clone-2".
+	Name string
+
+	// AllTasks applies to every task in the experiment.
+	AllTasks TaskOverrides
+
+	// Tasks apply to individual tasks by name, after AllTasks.
+	Tasks map[string]TaskOverrides
+}
+
+// maxCloneNames limits how many derived names are tried when cloning.
+const maxCloneNames = 10
+
+// Clone creates a new experiment from this experiment's spec, with overrides
+// applied. If workspace is empty, the clone is created in the same workspace
+// as the original. The new experiment's description notes its origin.
+func (h *ExperimentHandle) Clone(
+	ctx context.Context,
+	workspace string,
+	overrides *CloneOverrides,
+) (*api.Experiment, error) {
+	ctx = withOperation(ctx, "ExperimentHandle.Clone")
+
+	var o CloneOverrides
+	if overrides != nil {
+		o = *overrides
+	}
+
+	source, err := h.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := h.specV2(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.apply(spec); err != nil {
+		return nil, err
+	}
+
+	ref := source.ID
+	if source.FullName != "" {
+		ref = fmt.Sprintf("%s (%s)", source.FullName, source.ID)
+	}
+	provenance := "Cloned from experiment " + ref
+	if spec.Description == "" {
+		spec.Description = provenance
+	} else {
+		spec.Description += "\n\n" + provenance
+	}
+
+	target := h.client.Workspace(workspace)
+	if workspace == "" {
+		target = &WorkspaceHandle{client: h.client, ref: source.Workspace.ID}
+	}
+
+	if o.Name != "" || source.Name == "" {
+		return target.CreateExperiment(ctx, spec, &ExperimentOpts{Name: o.Name})
+	}
+
+	// Derived names may be taken by earlier clones, so try a few in turn.
+	base := source.Name + "-clone"
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		experiment, err := target.CreateExperiment(ctx, spec, &ExperimentOpts{Name: name})
+		if IsConflict(err) && i < maxCloneNames {
+			continue
+		}
+		return experiment, err
+	}
+}
+
+// specV2 retrieves an experiment's spec in version 2 format.
+func (h *ExperimentHandle) specV2(ctx context.Context) (*api.ExperimentSpecV2, error) {
+	body, err := h.Spec(ctx, "v2", true)
+	if err != nil {
+		return nil, err
+	}
+	defer safeClose(body)
+
+	var spec api.ExperimentSpecV2
+	if err := json.NewDecoder(body).Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	return &spec, nil
+}
+
+func (o *CloneOverrides) apply(spec *api.ExperimentSpecV2) error {
+	var unknown []string
+	for name := range o.Tasks {
+		found := false
+		for _, task := range spec.Tasks {
+			if task.Name == name {
+				found = true
+				break
+			}
+		}
+		if name == "" || !found {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("can't override missing tasks: %s", strings.Join(unknown, ", "))
+	}
+
+	for i := range spec.Tasks {
+		task := &spec.Tasks[i]
+		o.AllTasks.apply(task)
+		if overrides, ok := o.Tasks[task.Name]; ok {
+			overrides.apply(task)
+		}
+	}
+	return nil
+}
+
+func (o *TaskOverrides) apply(task *api.TaskSpecV2) {
+	if o.Image != nil {
+		task.Image = *o.Image
+	}
+	if o.Cluster != "" {
+		task.Context.Cluster = o.Cluster
+	}
+	if o.Priority != "" {
+		task.Context.Priority = o.Priority
+	}
+	for _, env := range o.EnvVars {
+		replaced := false
+		for i := range task.EnvVars {
+			if task.EnvVars[i].Name == env.Name {
+				task.EnvVars[i] = env
+				replaced = true
+			}
+		}
+		if !replaced {
+			task.EnvVars = append(task.EnvVars, env)
+		}
+	}
+	if o.Arguments != nil {
+		task.Arguments = append([]string(nil), o.Arguments...)
+	}
+}
@@ -18,6 +18,8 @@ func (c *Client) CreateCluster(
 	account string,
 	spec api.ClusterSpec,
 ) (*api.Cluster, error) {
+	ctx = withOperation(ctx, "Client.CreateCluster")
+
 	path := path.Join("/api/v3/clusters", url.PathEscape(account))
 	resp, err := c.sendRetryableRequest(ctx, http.MethodPost, path, nil, spec)
 	if err != nil {
@@ -44,6 +46,8 @@ func (c *Client) ListClusters(
 	account string,
 	opts *ListClusterOptions,
 ) ([]api.Cluster, string, error) {
+	ctx = withOperation(ctx, "Client.ListClusters")
+
 	if opts == nil {
 		opts = &ListClusterOptions{}
 	}
@@ -70,7 +74,7 @@ func (c *Client) ListClusters(
 
 // Cluster gets a handle for a cluster by name or ID. The reference is not resolved.
 func (c *Client) Cluster(reference string) *ClusterHandle {
-	return &ClusterHandle{client: c, ref: reference}
+	return &ClusterHandle{client: c, ref: c.qualifyWorkspace(reference)}
 }
 
 // ClusterHandle provides access to a single cluster.
@@ -86,10 +90,17 @@ func (h *ClusterHandle) Ref() string {
 
 // Get retrieves a clusters details.
 func (h *ClusterHandle) Get(ctx context.Context) (*api.Cluster, error) {
+	ctx = withOperation(ctx, "ClusterHandle.Get")
+
 	if err := validateClusterRef(h.ref); err != nil {
 		return nil, err
 	}
 
+	var cached api.Cluster
+	if h.client.cache.get(clusterKind, h.ref, &cached) {
+		return &cached, nil
+	}
+
 	path := path.Join("/api/v3/clusters", h.ref)
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -101,14 +112,31 @@ func (h *ClusterHandle) Get(ctx context.Context) (*api.Cluster, error) {
 	if err := parseResponse(resp, &result); err != nil {
 		return nil, err
 	}
+
+	h.client.cache.put(clusterKind, result.FullName, &result, h.ref)
 	return &result, nil
 }
 
+// Resolve returns a handle which refers to the cluster by its full name.
+// Clusters are addressed by name, so the handle isn't pinned to an ID.
+func (h *ClusterHandle) Resolve(ctx context.Context) (*ClusterHandle, error) {
+	ctx = withOperation(ctx, "ClusterHandle.Resolve")
+
+	cluster, err := h.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterHandle{client: h.client, ref: cluster.FullName}, nil
+}
+
 // Patch updates a cluster's details.
 func (h *ClusterHandle) Patch(ctx context.Context, patch *api.ClusterPatch) (*api.Cluster, error) {
+	ctx = withOperation(ctx, "ClusterHandle.Patch")
+
 	if err := validateClusterRef(h.ref); err != nil {
 		return nil, err
 	}
+	defer h.client.cache.invalidate(clusterKind, h.ref)
 
 	path := path.Join("/api/v3/clusters", h.ref)
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, patch)
@@ -129,9 +157,12 @@ func (h *ClusterHandle) Patch(ctx context.Context, patch *api.ClusterPatch) (*ap
 // New tasks cannot be created on the cluster, but existing scheduled tasks will
 // be allowed to complete.
 func (h *ClusterHandle) Terminate(ctx context.Context) error {
+	ctx = withOperation(ctx, "ClusterHandle.Terminate")
+
 	if err := validateClusterRef(h.ref); err != nil {
 		return err
 	}
+	defer h.client.cache.invalidate(clusterKind, h.ref)
 
 	path := path.Join("/api/v3/clusters", h.ref)
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodDelete, path, nil, nil)
@@ -144,6 +175,8 @@ func (h *ClusterHandle) Terminate(ctx context.Context) error {
 
 // CreateNode is meant for internal use only.
 func (h *ClusterHandle) CreateNode(ctx context.Context, spec api.NodeSpec) (*api.Node, error) {
+	ctx = withOperation(ctx, "ClusterHandle.CreateNode")
+
 	if err := validateClusterRef(h.ref); err != nil {
 		return nil, err
 	}
@@ -164,6 +197,8 @@ func (h *ClusterHandle) CreateNode(ctx context.Context, spec api.NodeSpec) (*api
 // ListClusterNodes enumerates all active nodes within a cluster.
 // TODO: Make this return an iterator.
 func (h *ClusterHandle) ListClusterNodes(ctx context.Context) ([]api.Node, error) {
+	ctx = withOperation(ctx, "ClusterHandle.ListClusterNodes")
+
 	if err := validateClusterRef(h.ref); err != nil {
 		return nil, err
 	}
@@ -192,6 +227,8 @@ func (h *ClusterHandle) ListExecutions(
 	ctx context.Context,
 	opts *ExecutionFilters,
 ) ([]api.Execution, error) {
+	ctx = withOperation(ctx, "ClusterHandle.ListExecutions")
+
 	if err := validateClusterRef(h.ref); err != nil {
 		return nil, err
 	}
@@ -223,6 +260,8 @@ func (h *ClusterHandle) PatchExecution(
 	execution string,
 	spec api.ExecutionPatchSpec,
 ) error {
+	ctx = withOperation(ctx, "ClusterHandle.PatchExecution")
+
 	if err := validateClusterRef(h.ref); err != nil {
 		return err
 	}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables which override configuration files.
+const (
+	EnvAddress   = "BEAKER_ADDR"
+	EnvToken     = "BEAKER_TOKEN"
+	EnvWorkspace = "BEAKER_WORKSPACE"
+	EnvOrg       = "BEAKER_ORG"
+	EnvUserAgent = "BEAKER_USER_AGENT"
+	EnvProfile   = "BEAKER_PROFILE"
+	EnvConfig    = "BEAKER_CONFIG"
+)
+
+// DefaultAddress is the address used when no other is configured.
+const DefaultAddress = "https://beaker.org"
+
+// Config holds settings used to create a client.
+type Config struct {
+	Address          string `yaml:"address,omitempty"`
+	Token            string `yaml:"token,omitempty"`
+	DefaultWorkspace string `yaml:"defaultWorkspace,omitempty"`
+	DefaultOrg       string `yaml:"defaultOrg,omitempty"`
+	UserAgent        string `yaml:"userAgent,omitempty"`
+
+	// Profile is the name of the profile selected from the config file, if any.
+	Profile string `yaml:"-"`
+
+	// Sources describes where each non-empty setting came from, keyed by its
+	// YAML name, such as "address".
+	Sources map[string]string `yaml:"-"`
+}
+
+// ConfigFile is the format of a config file. Settings at the top level apply
+// to every profile unless the selected profile overrides them.
+//
+//	address: https://beaker.org
+//	profile: dev
+//	profiles:
+//	  dev:
+//	    token: ...
+//	    defaultWorkspace: me/scratch
+type ConfigFile struct {
+	Config `yaml:",inline"`
+
+	// (optional) Profile names the profile to use when none is requested.
+	Profile string `yaml:"profile,omitempty"`
+
+	Profiles map[string]Config `yaml:"profiles,omitempty"`
+}
+
+// ConfigOptions controls how configuration is resolved. All fields are optional.
+type ConfigOptions struct {
+	// Path is the config file to read. If empty, the path is read from
+	// BEAKER_CONFIG, falling back to ~/.beaker/config.yml. Only the fallback
+	// may be missing.
+	Path string
+
+	// Profile selects a profile from the config file, taking precedence over
+	// BEAKER_PROFILE and the file's own default.
+	Profile string
+
+	// Overrides holds settings which take precedence over all other sources.
+	// Empty fields are ignored.
+	Overrides Config
+}
+
+// LoadConfig resolves client settings. Each setting is taken from the first
+// of these sources which supplies it:
+//
+//  1. Explicit overrides in opts.
+//  2. Environment variables, such as BEAKER_ADDR.
+//  3. The selected profile in the config file.
+//  4. The top level of the config file.
+//  5. DefaultAddress, for the address only.
+func LoadConfig(opts *ConfigOptions) (*Config, error) {
+	if opts == nil {
+		opts = &ConfigOptions{}
+	}
+
+	path, required := opts.Path, true
+	if path == "" {
+		path = os.Getenv(EnvConfig)
+	}
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("locating config file: %w", err)
+		}
+		path, required = filepath.Join(home, ".beaker", "config.yml"), false
+	}
+
+	file, err := readConfigFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		file, err = &ConfigFile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile, profileSource := opts.Profile, "explicit profile"
+	if profile == "" {
+		profile, profileSource = os.Getenv(EnvProfile), EnvProfile
+	}
+	if profile == "" {
+		profile, profileSource = file.Profile, path
+	}
+
+	config := &Config{Profile: profile, Sources: map[string]string{}}
+	config.merge(file.Config, path)
+	if profile != "" {
+		p, ok := file.Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("profile %q (from %s) not found in %s", profile, profileSource, path)
+		}
+		config.merge(p, fmt.Sprintf("profile %q in %s", profile, path))
+	}
+	config.set("address", &config.Address, os.Getenv(EnvAddress), EnvAddress)
+	config.set("token", &config.Token, os.Getenv(EnvToken), EnvToken)
+	config.set("defaultWorkspace", &config.DefaultWorkspace, os.Getenv(EnvWorkspace), EnvWorkspace)
+	config.set("defaultOrg", &config.DefaultOrg, os.Getenv(EnvOrg), EnvOrg)
+	config.set("userAgent", &config.UserAgent, os.Getenv(EnvUserAgent), EnvUserAgent)
+	config.merge(opts.Overrides, "explicit override")
+
+	if config.Address == "" {
+		config.Address = DefaultAddress
+		config.Sources["address"] = "default"
+	}
+	return config, nil
+}
+
+func readConfigFile(path string) (*ConfigFile, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var file ConfigFile
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// merge overwrites settings with non-empty values from other.
+func (c *Config) merge(other Config, source string) {
+	c.set("address", &c.Address, other.Address, source)
+	c.set("token", &c.Token, other.Token, source)
+	c.set("defaultWorkspace", &c.DefaultWorkspace, other.DefaultWorkspace, source)
+	c.set("defaultOrg", &c.DefaultOrg, other.DefaultOrg, source)
+	c.set("userAgent", &c.UserAgent, other.UserAgent, source)
+}
+
+// set overwrites a setting if value is non-empty, recording its source.
+func (c *Config) set(name string, dst *string, value, source string) {
+	if value == "" {
+		return
+	}
+	*dst = value
+	c.Sources[name] = source
+}
+
+// withSource attributes an option's error to the source of a setting.
+func (c *Config) withSource(name string, opt Option) Option {
+	return optionFunc(func(client *Client) error {
+		if err := opt.apply(client); err != nil {
+			return fmt.Errorf("%s from %s: %w", name, c.Sources[name], err)
+		}
+		return nil
+	})
+}
+
+// String describes the source of each setting without revealing the token.
+func (c *Config) String() string {
+	names := make([]string, 0, len(c.Sources))
+	for name := range c.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		if b.Len() > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s from %s", name, c.Sources[name])
+	}
+	return b.String()
+}
+
+// NewClientFromConfig creates a client from settings resolved by LoadConfig.
+// Options are applied after those derived from the configuration, so they take
+// precedence. The resolved configuration is returned along with the client.
+func NewClientFromConfig(opts *ConfigOptions, clientOpts ...Option) (*Client, *Config, error) {
+	config, err := LoadConfig(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := parseAddress(config.Address); err != nil {
+		return nil, nil, fmt.Errorf("address %q from %s: %w", config.Address, config.Sources["address"], err)
+	}
+
+	var configOpts []Option
+	if config.UserAgent != "" {
+		configOpts = append(configOpts, config.withSource("userAgent", WithUserAgent(config.UserAgent)))
+	}
+	if config.DefaultOrg != "" {
+		configOpts = append(configOpts, config.withSource("defaultOrg", WithDefaultOrg(config.DefaultOrg)))
+	}
+	if config.DefaultWorkspace != "" {
+		configOpts = append(configOpts, config.withSource("defaultWorkspace", WithDefaultWorkspace(config.DefaultWorkspace)))
+	}
+
+	client, err := NewClient(config.Address, config.Token, append(configOpts, clientOpts...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, config, nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setEnv sets environment variables for the duration of a test.
+func setEnv(t *testing.T, env map[string]string) {
+	for key, value := range env {
+		old, ok := os.LookupEnv(key)
+		require.NoError(t, os.Setenv(key, value))
+		key := key
+		t.Cleanup(func() {
+			if ok {
+				_ = os.Setenv(key, old)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+address: beaker.test
+token: base-token
+profile: dev
+profiles:
+  dev:
+    token: dev-token
+    defaultWorkspace: me/dev
+  prod:
+    address: https://prod.beaker.test
+    defaultOrg: ai2
+    userAgent: prod-tool
+`), 0644))
+
+	// Clear any variables set in the test environment.
+	setEnv(t, map[string]string{EnvAddress: "", EnvToken: "", EnvWorkspace: "", EnvOrg: "", EnvUserAgent: "", EnvProfile: ""})
+
+	config, err := LoadConfig(&ConfigOptions{Path: path})
+	require.NoError(t, err)
+	assert.Equal(t, "dev", config.Profile)
+	assert.Equal(t, "beaker.test", config.Address)
+	assert.Equal(t, "dev-token", config.Token)
+	assert.Equal(t, "me/dev", config.DefaultWorkspace)
+	assert.Equal(t, map[string]string{
+		"address":          path,
+		"token":            `profile "dev" in ` + path,
+		"defaultWorkspace": `profile "dev" in ` + path,
+	}, config.Sources)
+
+	setEnv(t, map[string]string{EnvProfile: "prod", EnvConfig: path})
+	config, err = LoadConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "prod-tool", config.UserAgent)
+	assert.Equal(t, `profile "prod" in `+path, config.Sources["userAgent"])
+
+	setEnv(t, map[string]string{EnvToken: "env-token", EnvUserAgent: "ci-tool"})
+	config, err = LoadConfig(&ConfigOptions{Overrides: Config{DefaultOrg: "me"}})
+	require.NoError(t, err)
+	assert.Equal(t, "prod", config.Profile)
+	assert.Equal(t, "https://prod.beaker.test", config.Address)
+	assert.Equal(t, "env-token", config.Token)
+	assert.Equal(t, "", config.DefaultWorkspace)
+	assert.Equal(t, "me", config.DefaultOrg)
+	assert.Equal(t, "ci-tool", config.UserAgent)
+	assert.Equal(t, EnvToken, config.Sources["token"])
+	assert.Equal(t, EnvUserAgent, config.Sources["userAgent"])
+	assert.Equal(t, "explicit override", config.Sources["defaultOrg"])
+
+	_, err = LoadConfig(&ConfigOptions{Profile: "missing"})
+	assert.EqualError(t, err, `profile "missing" (from explicit profile) not found in `+path)
+
+	_, err = LoadConfig(&ConfigOptions{Path: filepath.Join(dir, "missing.yml")})
+	assert.Error(t, err)
+
+	setEnv(t, map[string]string{EnvAddress: "https://beaker.test/path"})
+	_, _, err = NewClientFromConfig(nil)
+	assert.Contains(t, err.Error(), `address "https://beaker.test/path" from BEAKER_ADDR`)
+}
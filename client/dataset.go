@@ -2,12 +2,15 @@ package client
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/url"
 	"path"
 	"strconv"
+	"sync"
 	"time"
 
+	fhapi "github.com/beaker/fileheap/api"
 	fileheap "github.com/beaker/fileheap/client"
 
 	"github.com/beaker/client/api"
@@ -19,6 +22,12 @@ func (c *Client) CreateDataset(
 	spec api.DatasetSpec,
 	name string,
 ) (*DatasetHandle, error) {
+	ctx = withOperation(ctx, "Client.CreateDataset")
+
+	if spec.Workspace == "" {
+		spec.Workspace = c.workspaceRef()
+	}
+
 	query := url.Values{}
 	if name != "" {
 		query.Set("name", name)
@@ -54,9 +63,28 @@ func (h *DatasetHandle) Ref() string {
 	return h.ref
 }
 
+// path returns the API path of the dataset, or of elem within it.
+func (h *DatasetHandle) path(ctx context.Context, elem ...string) (string, error) {
+	ref, err := h.client.resolveItem(ctx, datasetKind, h.ref)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(append([]string{"/api/v3/datasets", url.PathEscape(ref)}, elem...)...), nil
+}
+
 // Get retrieves a dataset's details.
 func (h *DatasetHandle) Get(ctx context.Context) (*api.Dataset, error) {
-	uri := path.Join("/api/v3/datasets", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "DatasetHandle.Get")
+
+	var cached api.Dataset
+	if h.client.cache.get(datasetKind, h.ref, &cached) {
+		return &cached, nil
+	}
+
+	uri, err := h.path(ctx)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -67,17 +95,41 @@ func (h *DatasetHandle) Get(ctx context.Context) (*api.Dataset, error) {
 	if err := parseResponse(resp, &body); err != nil {
 		return nil, err
 	}
+
+	h.client.cache.put(datasetKind, body.ID, &body, h.ref, body.FullName)
 	return &body, nil
 }
 
+// Resolve returns a handle pinned to the dataset's ID, so it continues to
+// refer to the same dataset if its name is changed or reused.
+func (h *DatasetHandle) Resolve(ctx context.Context) (*DatasetHandle, error) {
+	ctx = withOperation(ctx, "DatasetHandle.Resolve")
+
+	if isID(h.ref) {
+		return h, nil
+	}
+
+	dataset, err := h.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &DatasetHandle{client: h.client, ref: dataset.ID}, nil
+}
+
 // Storage gets a client to access a dataset's backing storage. The returned
 // client expires at the returned time and must be discarded and replaced.
+// See RenewingStorage for long-running transfers.
 func (h *DatasetHandle) Storage(ctx context.Context) (
 	client *fileheap.DatasetRef,
 	expiry time.Time,
 	err error,
 ) {
-	uri := path.Join("/api/v3/datasets", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "DatasetHandle.Storage")
+
+	uri, err := h.path(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, uri, nil, nil)
 	if err != nil {
 		return nil, time.Time{}, err
@@ -97,9 +149,107 @@ func (h *DatasetHandle) Storage(ctx context.Context) (
 	return fh.Dataset(body.Storage.ID), body.Storage.TokenExpires, nil
 }
 
+// storageRenewMargin is how long before expiry storage credentials are renewed.
+const storageRenewMargin = 5 * time.Minute
+
+// RenewingStorage provides access to a dataset's backing storage, replacing
+// credentials before they expire. It is safe for concurrent use.
+//
+// Credentials are checked at the start of each operation, so an operation
+// begun with valid credentials isn't interrupted by renewal. However, storage
+// splits large files into parts internally, and credentials can't be replaced
+// between parts. If credentials are rejected part way through, Do restarts the
+// whole operation, so a large upload starts again from its first byte.
+// Credentials are renewed when within five minutes of expiry, so this only
+// affects operations which take longer than that.
+type RenewingStorage struct {
+	dataset *DatasetHandle
+
+	mu     sync.Mutex
+	ref    *fileheap.DatasetRef
+	expiry time.Time
+}
+
+// RenewingStorage gets a self-renewing accessor for a dataset's backing
+// storage. Unlike Storage, the accessor remains usable indefinitely.
+func (h *DatasetHandle) RenewingStorage(ctx context.Context) (*RenewingStorage, error) {
+	ctx = withOperation(ctx, "DatasetHandle.RenewingStorage")
+
+	s := &RenewingStorage{dataset: h}
+	if _, err := s.Ref(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Ref returns a client for the dataset's storage, renewing credentials if
+// they're near expiry. Call Ref for each operation rather than holding onto
+// the returned client.
+func (s *RenewingStorage) Ref(ctx context.Context) (*fileheap.DatasetRef, error) {
+	ctx = withOperation(ctx, "RenewingStorage.Ref")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ref == nil || time.Until(s.expiry) < storageRenewMargin {
+		if err := s.renew(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return s.ref, nil
+}
+
+// Do calls fn with a client for the dataset's storage. If storage rejects
+// the client's credentials, they are renewed and fn is called once more, so
+// fn must be safe to repeat.
+func (s *RenewingStorage) Do(ctx context.Context, fn func(*fileheap.DatasetRef) error) error {
+	ctx = withOperation(ctx, "RenewingStorage.Do")
+
+	ref, err := s.Ref(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(ref)
+	var fhErr fhapi.Error
+	if !errors.As(err, &fhErr) ||
+		(fhErr.Code != http.StatusUnauthorized && fhErr.Code != http.StatusForbidden) {
+		return err
+	}
+
+	s.mu.Lock()
+	// Another caller may have renewed credentials already.
+	if s.ref == ref {
+		if err := s.renew(ctx); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+	}
+	ref = s.ref
+	s.mu.Unlock()
+
+	return fn(ref)
+}
+
+// renew replaces storage credentials. The caller must hold s.mu.
+func (s *RenewingStorage) renew(ctx context.Context) error {
+	ref, expiry, err := s.dataset.Storage(ctx)
+	if err != nil {
+		return err
+	}
+	s.ref, s.expiry = ref, expiry
+	return nil
+}
+
 // SetName sets a dataset's name.
 func (h *DatasetHandle) SetName(ctx context.Context, name string) error {
-	path := path.Join("/api/v3/datasets", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "DatasetHandle.SetName")
+
+	defer h.client.cache.invalidate(datasetKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.DatasetPatchSpec{Name: &name}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -111,7 +261,13 @@ func (h *DatasetHandle) SetName(ctx context.Context, name string) error {
 
 // SetDescription sets a dataset's description.
 func (h *DatasetHandle) SetDescription(ctx context.Context, description string) error {
-	path := path.Join("/api/v3/datasets", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "DatasetHandle.SetDescription")
+
+	defer h.client.cache.invalidate(datasetKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.DatasetPatchSpec{Description: &description}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -124,7 +280,13 @@ func (h *DatasetHandle) SetDescription(ctx context.Context, description string)
 // Commit finalizes a dataset, unblocking usage and locking it for further
 // writes. The dataset is guaranteed to remain uncommitted on failure.
 func (h *DatasetHandle) Commit(ctx context.Context) error {
-	path := path.Join("/api/v3/datasets", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "DatasetHandle.Commit")
+
+	defer h.client.cache.invalidate(datasetKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.DatasetPatchSpec{Commit: true}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -136,7 +298,13 @@ func (h *DatasetHandle) Commit(ctx context.Context) error {
 
 // Delete a dataset. Note that this action is not reversible.
 func (h *DatasetHandle) Delete(ctx context.Context) error {
-	path := path.Join("/api/v3/datasets", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "DatasetHandle.Delete")
+
+	defer h.client.cache.invalidate(datasetKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodDelete, path, nil, nil)
 	if err != nil {
 		return err
@@ -150,6 +318,8 @@ func (c *Client) SearchDatasets(
 	searchOptions api.DatasetSearchOptions,
 	page int,
 ) ([]api.Dataset, error) {
+	ctx = withOperation(ctx, "Client.SearchDatasets")
+
 	query := url.Values{"page": {strconv.Itoa(page)}}
 	resp, err := c.sendRetryableRequest(ctx, http.MethodPost, "/api/v3/datasets/search", query, searchOptions)
 	if err != nil {
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	fhapi "github.com/beaker/fileheap/api"
+	fileheap "github.com/beaker/fileheap/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewingStorageExpiry(t *testing.T) {
+	ctx := context.Background()
+
+	// Credentials well before expiry are reused.
+	storage := newFakeStorage(t, nil)
+	s, err := storage.client.Dataset("ds1").RenewingStorage(ctx)
+	require.NoError(t, err)
+	_, err = s.Ref(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, storage.renewals)
+
+	// Credentials within the margin of expiry are renewed on each use.
+	storage = newFakeStorage(t, nil)
+	storage.ttl = storageRenewMargin - time.Minute
+	s, err = storage.client.Dataset("ds1").RenewingStorage(ctx)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, s.Do(ctx, func(ref *fileheap.DatasetRef) error {
+			_, err := ref.FileInfo(ctx, "missing")
+			if err == fileheap.ErrFileNotFound {
+				return nil
+			}
+			return err
+		}))
+	}
+	assert.Equal(t, 3, storage.renewals)
+}
+
+func TestRenewingStorageRetry(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeStorage(t, map[string]string{"a.txt": "alpha"})
+	s, err := storage.client.Dataset("ds1").RenewingStorage(ctx)
+	require.NoError(t, err)
+
+	// Revoke the current credentials. Storage accepts the next ones issued.
+	storage.mu.Lock()
+	storage.token = "revoked"
+	storage.mu.Unlock()
+
+	calls := 0
+	err = s.Do(ctx, func(ref *fileheap.DatasetRef) error {
+		calls++
+		r, err := ref.ReadFile(ctx, "a.txt")
+		if err == nil {
+			r.Close()
+		}
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 2, storage.renewals)
+}
+
+func TestRenewingStorageRetryOnce(t *testing.T) {
+	ctx := context.Background()
+	storage := newFakeStorage(t, map[string]string{"a.txt": "alpha"})
+	s, err := storage.client.Dataset("ds1").RenewingStorage(ctx)
+	require.NoError(t, err)
+
+	// Storage rejects every request, even with renewed credentials.
+	storage.setHandler(func(w http.ResponseWriter, r *http.Request, file string) bool {
+		writeStorageError(w, http.StatusForbidden)
+		return true
+	})
+
+	calls := 0
+	err = s.Do(ctx, func(ref *fileheap.DatasetRef) error {
+		calls++
+		r, err := ref.ReadFile(ctx, "a.txt")
+		if err == nil {
+			r.Close()
+		}
+		return err
+	})
+	var fhErr fhapi.Error
+	require.True(t, errors.As(err, &fhErr), "unexpected error: %v", err)
+	assert.Equal(t, http.StatusForbidden, fhErr.Code)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 2, storage.renewals)
+
+	// Other errors aren't retried.
+	storage.setHandler(func(w http.ResponseWriter, r *http.Request, file string) bool {
+		writeStorageError(w, http.StatusBadRequest)
+		return true
+	})
+	calls = 0
+	err = s.Do(ctx, func(ref *fileheap.DatasetRef) error {
+		calls++
+		_, err := ref.ReadFile(ctx, "a.txt")
+		return err
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
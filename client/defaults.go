@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/beaker/client/api"
+)
+
+// WithDefaultOrg sets the organization used when none is given. Unqualified
+// workspace and cluster names are qualified by it, workspaces are created in it,
+// and it's the organization searched by ListWorkspaces.
+func WithDefaultOrg(org string) Option {
+	return optionFunc(func(c *Client) error {
+		if org == "" || strings.Contains(org, "/") {
+			return errors.New("default organization must be a non-empty name without '/'")
+		}
+		c.defaultOrg = org
+		return nil
+	})
+}
+
+// WithDefaultWorkspace sets the workspace used when none is given. Datasets,
+// groups, and images are created in it unless their specs name a workspace.
+//
+// Unqualified names of datasets, experiments, groups, and images refer to items
+// in the workspace. For example, Dataset("my-data") refers to the dataset
+// named "my-data" in the default workspace, whichever account it belongs to.
+// Each request through such a handle first looks the name up in the workspace;
+// use Resolve to look it up once. Without a default workspace, unqualified
+// names are resolved by the service relative to the authenticated user.
+func WithDefaultWorkspace(workspace string) Option {
+	return optionFunc(func(c *Client) error {
+		if workspace == "" {
+			return errors.New("default workspace must not be empty")
+		}
+		c.defaultWorkspace = workspace
+		return nil
+	})
+}
+
+// workspaceRef returns the default workspace, qualified by the default
+// organization if necessary. It's empty if there is no default workspace.
+func (c *Client) workspaceRef() string {
+	return c.qualifyWorkspace(c.defaultWorkspace)
+}
+
+// qualifyWorkspace qualifies an unqualified workspace or cluster name by the
+// default organization.
+func (c *Client) qualifyWorkspace(ref string) string {
+	return qualify(c.defaultOrg, ref)
+}
+
+// resolveItem returns the ref with which to request a dataset, experiment,
+// group, or image. If there is a default workspace, an unqualified name is
+// looked up in it and replaced by the ID of the item with that name.
+func (c *Client) resolveItem(ctx context.Context, kind, ref string) (string, error) {
+	workspace := c.workspaceRef()
+	if workspace == "" || ref == "" || strings.Contains(ref, "/") || isID(ref) {
+		return ref, nil
+	}
+
+	var ids []string
+	match := func(id, name string) {
+		if name == ref {
+			ids = append(ids, id)
+		}
+	}
+
+	var err error
+	w := c.Workspace(workspace)
+	switch kind {
+	case datasetKind:
+		it := w.IterateDatasets(ctx, &ListDatasetOptions{Text: ref}, nil)
+		for it.Next() {
+			match(it.Value().ID, it.Value().Name)
+		}
+		err = it.Err()
+	case experimentKind:
+		it := w.IterateExperiments(ctx, &ListExperimentOptions{Text: ref}, nil)
+		for it.Next() {
+			match(it.Value().ID, it.Value().Name)
+		}
+		err = it.Err()
+	case groupKind:
+		it := w.IterateGroups(ctx, &ListGroupOptions{Text: ref}, nil)
+		for it.Next() {
+			match(it.Value().ID, it.Value().Name)
+		}
+		err = it.Err()
+	case imageKind:
+		it := w.IterateImages(ctx, &ListImageOptions{Text: ref}, nil)
+		for it.Next() {
+			match(it.Value().ID, it.Value().Name)
+		}
+		err = it.Err()
+	default:
+		return "", fmt.Errorf("can't resolve %s names in a workspace", kind)
+	}
+	if err != nil {
+		return "", fmt.Errorf("looking up %s %q in workspace %s: %w", kind, ref, workspace, err)
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", api.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("%s %q not found in workspace %s", kind, ref, workspace),
+		}
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%s name %q is ambiguous in workspace %s; use a full name or ID", kind, ref, workspace)
+	}
+}
+
+// qualify prefixes a name with an account unless it's already qualified or is an ID.
+func qualify(account, ref string) string {
+	if account == "" || ref == "" || strings.Contains(ref, "/") || isID(ref) {
+		return ref
+	}
+	return account + "/" + ref
+}
+
+// isID reports whether a reference is an ID rather than a name. IDs are
+// 26-character ULIDs in Crockford's base32.
+func isID(ref string) bool {
+	if len(ref) != 26 {
+		return false
+	}
+	for _, r := range ref {
+		if !(r >= '0' && r <= '9' || r >= 'A' && r <= 'Z') || r == 'I' || r == 'L' || r == 'O' || r == 'U' {
+			return false
+		}
+	}
+	return true
+}
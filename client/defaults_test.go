@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beaker/client/api"
+)
+
+func TestDefaultScope(t *testing.T) {
+	client, err := NewClient("beaker.test", "", WithDefaultOrg("ai2"), WithDefaultWorkspace("my-ws"))
+	require.NoError(t, err)
+
+	const id = "01F8GQ3Y4ZKX7W9M2N5P6R0T1V"
+	cases := map[string]struct {
+		Ref      string
+		Expected string
+	}{
+		"Workspace":          {client.Workspace("other-ws").Ref(), "ai2/other-ws"},
+		"QualifiedWorkspace": {client.Workspace("me/ws").Ref(), "me/ws"},
+		"Cluster":            {client.Cluster("cpu").Ref(), "ai2/cpu"},
+		"Dataset":            {client.Dataset("my-data").Ref(), "my-data"},
+		"Experiment":         {client.Experiment("run1").Ref(), "run1"},
+		"Group":              {client.Group("sweep").Ref(), "sweep"},
+		"Image":              {client.Image("trainer").Ref(), "trainer"},
+		"QualifiedDataset":   {client.Dataset("me/data").Ref(), "me/data"},
+		"ID":                 {client.Experiment(id).Ref(), id},
+		"Lowercase":          {client.Workspace("01f8gq3y4zkx7w9m2n5p6r0t1v").Ref(), "ai2/01f8gq3y4zkx7w9m2n5p6r0t1v"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, c.Ref)
+		})
+	}
+
+	_, err = NewClient("beaker.test", "", WithDefaultOrg("ai2/ws"))
+	assert.Error(t, err)
+}
+
+func TestDefaultScopeRequests(t *testing.T) {
+	var workspace, org string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/datasets":
+			var spec api.DatasetSpec
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&spec))
+			workspace = spec.Workspace
+			_, _ = w.Write([]byte(`{"id": "d1"}`))
+		case "/api/v3/workspaces":
+			org = r.URL.Query().Get("org")
+			_, _ = w.Write([]byte(`{"data": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "", WithDefaultOrg("ai2"), WithDefaultWorkspace("my-ws"))
+	require.NoError(t, err)
+
+	_, err = c.CreateDataset(context.Background(), api.DatasetSpec{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "ai2/my-ws", workspace)
+
+	_, err = c.CreateDataset(context.Background(), api.DatasetSpec{Workspace: "me/ws"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "me/ws", workspace)
+
+	_, _, err = c.ListWorkspaces(context.Background(), "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ai2", org)
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/beaker/client/api"
+)
+
+// ResponseError describes a failed request whose response body could not be
+// interpreted as an api.Error, such as an HTML page from a proxy.
+type ResponseError struct {
+	// Method and Path identify the request which failed.
+	Method string
+	Path   string
+
+	// StatusCode is the HTTP status code of the response, such as 502.
+	StatusCode int
+
+	// Body is the raw response body.
+	Body string
+}
+
+// Error implements the standard error interface.
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s: %s",
+		e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// StatusCode returns the HTTP status code associated with an error, or zero if
+// the error did not come from a response.
+func StatusCode(err error) int {
+	var apiErr api.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode
+	}
+	return 0
+}
+
+// ErrorID returns the service's identifier for an error, if any. Include the
+// identifier when reporting an issue so it can be traced.
+func ErrorID(err error) string {
+	var apiErr api.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorID
+	}
+	return ""
+}
+
+// IsNotFound returns whether an error indicates a resource doesn't exist.
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// IsConflict returns whether an error indicates a conflict with existing
+// state, such as a name collision.
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+// IsUnauthorized returns whether an error indicates missing or invalid credentials.
+func IsUnauthorized(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+// IsForbidden returns whether an error indicates the caller lacks permission.
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+// IsRateLimited returns whether an error indicates too many requests were sent.
+func IsRateLimited(err error) bool {
+	return StatusCode(err) == http.StatusTooManyRequests
+}
+
+// IsRetryable returns whether a failed request may succeed if sent again. This
+// includes rate limits, server errors, and network timeouts.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	code := StatusCode(err)
+	if code == http.StatusTooManyRequests || (code >= 500 && code != http.StatusNotImplemented) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beaker/client/api"
+)
+
+func TestErrorFromResponse(t *testing.T) {
+	newResponse := func(status int, body string) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request: &http.Request{
+				Method: http.MethodGet,
+				URL:    &url.URL{Path: "/api/v3/datasets/foo"},
+			},
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		assert.NoError(t, errorFromResponse(newResponse(http.StatusOK, "{}")))
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		err := errorFromResponse(newResponse(http.StatusNotFound,
+			`{"message":"dataset not found","error_id":"abc123"}`))
+		require.Error(t, err)
+		assert.Equal(t, api.Error{Code: 404, Message: "dataset not found", ErrorID: "abc123"}, err)
+		assert.True(t, IsNotFound(err))
+		assert.False(t, IsRetryable(err))
+		assert.Equal(t, "abc123", ErrorID(err))
+
+		wrapped := fmt.Errorf("getting dataset: %w", err)
+		assert.True(t, IsNotFound(wrapped))
+		assert.Equal(t, "abc123", ErrorID(wrapped))
+	})
+
+	t.Run("NotJSON", func(t *testing.T) {
+		err := errorFromResponse(newResponse(http.StatusBadGateway, "<html>Bad Gateway</html>"))
+		require.Error(t, err)
+		assert.Equal(t, &ResponseError{
+			Method:     http.MethodGet,
+			Path:       "/api/v3/datasets/foo",
+			StatusCode: http.StatusBadGateway,
+			Body:       "<html>Bad Gateway</html>",
+		}, err)
+		assert.Equal(t, http.StatusBadGateway, StatusCode(err))
+		assert.True(t, IsRetryable(err))
+		assert.Empty(t, ErrorID(err))
+	})
+}
+
+func TestErrorPredicates(t *testing.T) {
+	cases := map[int]func(error) bool{
+		http.StatusNotFound:        IsNotFound,
+		http.StatusConflict:        IsConflict,
+		http.StatusUnauthorized:    IsUnauthorized,
+		http.StatusForbidden:       IsForbidden,
+		http.StatusTooManyRequests: IsRateLimited,
+	}
+
+	for code, predicate := range cases {
+		t.Run(http.StatusText(code), func(t *testing.T) {
+			assert.True(t, predicate(api.Error{Code: code, Message: "failed"}))
+			assert.True(t, predicate(&ResponseError{StatusCode: code}))
+			assert.False(t, predicate(api.Error{Code: http.StatusTeapot, Message: "failed"}))
+			assert.False(t, predicate(nil))
+		})
+	}
+}
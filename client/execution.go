@@ -30,6 +30,8 @@ func (h *ExecutionHandle) Ref() string {
 
 // Get retrieves an execution's details.
 func (h *ExecutionHandle) Get(ctx context.Context) (*api.Execution, error) {
+	ctx = withOperation(ctx, "ExecutionHandle.Get")
+
 	path := path.Join("/api/v3/executions", url.PathEscape(h.id))
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -47,8 +49,18 @@ func (h *ExecutionHandle) Get(ctx context.Context) (*api.Execution, error) {
 // GetLogs gets all logs for a task. Logs are in the form:
 // {RFC3339 nano timestamp} {message}\n
 func (h *ExecutionHandle) GetLogs(ctx context.Context) (io.ReadCloser, error) {
+	ctx = withOperation(ctx, "ExecutionHandle.GetLogs")
+	return h.getLogs(ctx, time.Time{})
+}
+
+// getLogs gets logs for a task, starting at since if it's set.
+func (h *ExecutionHandle) getLogs(ctx context.Context, since time.Time) (io.ReadCloser, error) {
 	path := path.Join("/api/v3/executions", url.PathEscape(h.id), "logs")
-	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
+	var query url.Values
+	if !since.IsZero() {
+		query = url.Values{"since": {since.Format(time.RFC3339Nano)}}
+	}
+	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, query, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -59,19 +71,194 @@ func (h *ExecutionHandle) GetLogs(ctx context.Context) (io.ReadCloser, error) {
 	return resp.Body, nil
 }
 
+// FollowOptions configures how logs are followed. All fields are optional.
+type FollowOptions struct {
+	// Since skips all log lines written before the given time.
+	Since time.Time
+
+	// PollInterval is the time to wait for new lines. Defaults to 2 seconds.
+	PollInterval time.Duration
+}
+
+// FollowLogs streams an execution's logs as they're written, until the
+// execution is finalized and all of its logs have been read. If the
+// connection is interrupted, the stream resumes after the last line read.
+func (h *ExecutionHandle) FollowLogs(ctx context.Context, opts *FollowOptions) *LogStream {
+	ctx = withOperation(ctx, "ExecutionHandle.FollowLogs")
+
+	s := &LogStream{ctx: ctx, execution: h, pollInterval: 2 * time.Second}
+	if opts != nil {
+		s.since = opts.Since
+		if opts.PollInterval > 0 {
+			s.pollInterval = opts.PollInterval
+		}
+	}
+	return s
+}
+
+// LogStream reads an execution's logs as they're written.
+type LogStream struct {
+	ctx          context.Context
+	execution    *ExecutionHandle
+	pollInterval time.Duration
+
+	// Time of the last line read, and how many lines have been read with
+	// exactly that time. Together these allow resuming without duplicates.
+	since   time.Time
+	atSince int
+
+	// Time of the last timestamped line, which is given to continuation lines
+	// at the start of the next chunk.
+	last time.Time
+
+	// Number of consecutive polls which failed to read logs.
+	failures int
+
+	polled  bool // Whether logs have been fetched at least once.
+	records []LogRecord
+	current LogRecord
+	done    bool
+	err     error
+}
+
+// Next waits for the next log line. It returns false when the execution is
+// finalized and all lines have been read, or if an error occurs, after which
+// Err reports the error, if any.
+func (s *LogStream) Next() bool {
+	for len(s.records) == 0 {
+		if s.done || s.err != nil {
+			return false
+		}
+
+		if s.polled {
+			select {
+			case <-s.ctx.Done():
+				s.err = s.ctx.Err()
+				return false
+			case <-time.After(s.pollInterval):
+			}
+		}
+		s.polled = true
+
+		// Check for finalization before reading logs so no lines are missed
+		// between reading and finalizing.
+		execution, err := s.execution.Get(s.ctx)
+		if err != nil {
+			s.err = err
+			return false
+		}
+		finalized := execution.State.Finalized != nil
+
+		// Lines read before an error are still returned before the error.
+		complete, err := s.poll()
+		if err != nil {
+			s.err = err
+			continue
+		}
+		if s.ctx.Err() != nil {
+			s.err = s.ctx.Err()
+			return false
+		}
+
+		// If reading was interrupted, more lines may remain; the next poll
+		// resumes from the last line read.
+		if finalized && complete {
+			s.done = true
+		}
+	}
+
+	s.current, s.records = s.records[0], s.records[1:]
+	return true
+}
+
+// maxLogReadFailures is the number of consecutive polls which may fail to read
+// logs before a stream gives up.
+const maxLogReadFailures = 5
+
+// poll reads new log lines into the stream's buffer. It returns whether all
+// available lines were read, or an error if logs couldn't be requested or
+// reading failed too many times in a row.
+func (s *LogStream) poll() (bool, error) {
+	logs, err := s.execution.getLogs(s.ctx, s.since)
+	if err != nil {
+		return false, err
+	}
+	defer safeClose(logs)
+
+	scanner := newLogScanner(logs)
+	scanner.last = s.last
+	defer func() { s.last = scanner.last }()
+
+	// Skip lines which were already read. The service may include lines
+	// written at exactly the requested time.
+	skip := s.atSince
+	leading := true // Whether no timestamped line has been scanned yet.
+	for {
+		r, err := scanner.scan()
+		if err == io.EOF {
+			s.failures = 0
+			return true, nil
+		}
+		if err != nil {
+			// Reading was interrupted; the next poll resumes after the last
+			// line read, unless reads keep failing.
+			if s.failures++; s.failures >= maxLogReadFailures {
+				return false, err
+			}
+			return false, nil
+		}
+
+		if !scanner.continued {
+			leading = false
+		} else if leading && !s.last.IsZero() {
+			// Lines continuing the last line of the previous poll are new,
+			// since the service resends lines only with their timestamp.
+			if r.Time.Equal(s.since) {
+				s.atSince++
+			}
+			s.records = append(s.records, r)
+			continue
+		}
+
+		if r.Time.Before(s.since) {
+			continue
+		}
+		if r.Time.Equal(s.since) {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			s.atSince++
+		} else {
+			s.since = r.Time
+			s.atSince = 1
+			skip = 0
+		}
+		s.records = append(s.records, r)
+	}
+}
+
+// Value returns the current log line. It is only valid after Next returns true.
+func (s *LogStream) Value() LogRecord {
+	return s.current
+}
+
+// Err returns the first error encountered while following logs, if any.
+func (s *LogStream) Err() error {
+	return s.err
+}
+
 // PutLogs uploads a log chunk. Since is the time of the first log message in the chunk.
 func (h *ExecutionHandle) PutLogs(ctx context.Context, filename string, logs io.Reader) error {
+	ctx = withOperation(ctx, "ExecutionHandle.PutLogs")
+
 	path := path.Join("/api/v3/executions", url.PathEscape(h.id), "logs", filename)
 	req, err := h.client.newRequest(http.MethodPut, path, nil, logs)
 	if err != nil {
 		return err
 	}
 
-	client := &http.Client{
-		Timeout:       30 * time.Second,
-		CheckRedirect: copyRedirectHeader,
-	}
-	resp, err := client.Do(req.WithContext(ctx))
+	resp, err := h.client.httpClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return err
 	}
@@ -81,6 +268,8 @@ func (h *ExecutionHandle) PutLogs(ctx context.Context, filename string, logs io.
 
 // GetResults retrieves an execution's results.
 func (h *ExecutionHandle) GetResults(ctx context.Context) (*api.ExecutionResults, error) {
+	ctx = withOperation(ctx, "ExecutionHandle.GetResults")
+
 	path := path.Join("/api/v3/executions", url.PathEscape(h.id), "results")
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -98,6 +287,8 @@ func (h *ExecutionHandle) GetResults(ctx context.Context) (*api.ExecutionResults
 
 // PostStatus updates an execution's current status.
 func (h *ExecutionHandle) PostStatus(ctx context.Context, status api.ExecStatusUpdate) error {
+	ctx = withOperation(ctx, "ExecutionHandle.PostStatus")
+
 	path := path.Join("/api/v3/executions", url.PathEscape(h.id), "status")
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPost, path, nil, status)
 	if err != nil {
@@ -109,6 +300,8 @@ func (h *ExecutionHandle) PostStatus(ctx context.Context, status api.ExecStatusU
 
 // Stop an execution and optionally queue it to run again.
 func (h *ExecutionHandle) Stop(ctx context.Context, requeue bool) error {
+	ctx = withOperation(ctx, "ExecutionHandle.Stop")
+
 	path := path.Join("/api/v3/executions", url.PathEscape(h.id), "stop")
 	query := url.Values{"requeue": {strconv.FormatBool(requeue)}}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPost, path, query, nil)
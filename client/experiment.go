@@ -2,6 +2,8 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -28,9 +30,39 @@ func (h *ExperimentHandle) Ref() string {
 	return h.ref
 }
 
+// path returns the API path of the experiment, or of elem within it.
+func (h *ExperimentHandle) path(ctx context.Context, elem ...string) (string, error) {
+	ref, err := h.client.resolveItem(ctx, experimentKind, h.ref)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(append([]string{"/api/v3/experiments", url.PathEscape(ref)}, elem...)...), nil
+}
+
 // Get retrieves an experiment's details, including a summary of contained tasks.
 func (h *ExperimentHandle) Get(ctx context.Context) (*api.Experiment, error) {
-	path := path.Join("/api/v3/experiments", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "ExperimentHandle.Get")
+
+	var cached api.Experiment
+	if h.client.cache.get(experimentKind, h.ref, &cached) {
+		return &cached, nil
+	}
+
+	experiment, err := h.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	h.client.cache.put(experimentKind, experiment.ID, experiment, h.ref, experiment.FullName)
+	return experiment, nil
+}
+
+// get retrieves an experiment's details, bypassing the cache.
+func (h *ExperimentHandle) get(ctx context.Context) (*api.Experiment, error) {
+	path, err := h.path(ctx)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, err
@@ -45,9 +77,30 @@ func (h *ExperimentHandle) Get(ctx context.Context) (*api.Experiment, error) {
 	return &experiment, nil
 }
 
+// Resolve returns a handle pinned to the experiment's ID, so it continues to
+// refer to the same experiment if its name is changed or reused.
+func (h *ExperimentHandle) Resolve(ctx context.Context) (*ExperimentHandle, error) {
+	ctx = withOperation(ctx, "ExperimentHandle.Resolve")
+
+	if isID(h.ref) {
+		return h, nil
+	}
+
+	experiment, err := h.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ExperimentHandle{client: h.client, ref: experiment.ID}, nil
+}
+
 // Groups gets the ID of each group that the experiment belongs to.
 func (h *ExperimentHandle) Groups(ctx context.Context) ([]string, error) {
-	path := path.Join("/api/v3/experiments", url.PathEscape(h.ref), "groups")
+	ctx = withOperation(ctx, "ExperimentHandle.Groups")
+
+	path, err := h.path(ctx, "groups")
+	if err != nil {
+		return nil, err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, err
@@ -63,7 +116,13 @@ func (h *ExperimentHandle) Groups(ctx context.Context) ([]string, error) {
 
 // SetName sets an experiment's name.
 func (h *ExperimentHandle) SetName(ctx context.Context, name string) error {
-	path := path.Join("/api/v3/experiments", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "ExperimentHandle.SetName")
+
+	defer h.client.cache.invalidate(experimentKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.ExperimentPatchSpec{Name: &name}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -75,7 +134,13 @@ func (h *ExperimentHandle) SetName(ctx context.Context, name string) error {
 
 // SetDescription sets an experiment's description
 func (h *ExperimentHandle) SetDescription(ctx context.Context, description string) error {
-	path := path.Join("/api/v3/experiments", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "ExperimentHandle.SetDescription")
+
+	defer h.client.cache.invalidate(experimentKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.ExperimentPatchSpec{Description: &description}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -88,7 +153,12 @@ func (h *ExperimentHandle) SetDescription(ctx context.Context, description strin
 // Spec gets the experiment specification.
 // Default format is YAML. JSON is available by setting json=true.
 func (h *ExperimentHandle) Spec(ctx context.Context, version string, json bool) (io.ReadCloser, error) {
-	path := path.Join("/api/v3/experiments", url.PathEscape(h.ref), "spec")
+	ctx = withOperation(ctx, "ExperimentHandle.Spec")
+
+	path, err := h.path(ctx, "spec")
+	if err != nil {
+		return nil, err
+	}
 	req, err := h.client.newRetryableRequest("GET", path, url.Values{
 		"version": []string{version},
 	}, nil)
@@ -99,10 +169,7 @@ func (h *ExperimentHandle) Spec(ctx context.Context, version string, json bool)
 		req.Header.Set("Accept", "application/json")
 	}
 
-	resp, err := newRetryableClient(&http.Client{
-		Timeout:       30 * time.Second,
-		CheckRedirect: copyRedirectHeader,
-	}, h.client.HTTPResponseHook).Do(req.WithContext(ctx))
+	resp, err := h.client.do(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +182,13 @@ func (h *ExperimentHandle) Spec(ctx context.Context, version string, json bool)
 
 // Resume retries failed or stopped tasks within a previously run experiment.
 func (h *ExperimentHandle) Resume(ctx context.Context) error {
-	path := path.Join("/api/v3/experiments", url.PathEscape(h.ref), "/resume")
+	ctx = withOperation(ctx, "ExperimentHandle.Resume")
+
+	defer h.client.cache.invalidate(experimentKind, h.ref)
+	path, err := h.path(ctx, "/resume")
+	if err != nil {
+		return err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPost, path, nil, nil)
 	if err != nil {
 		return err
@@ -127,7 +200,13 @@ func (h *ExperimentHandle) Resume(ctx context.Context) error {
 // Stop cancels all uncompleted tasks for an experiment. If the experiment has
 // already completed, this succeeds without effect.
 func (h *ExperimentHandle) Stop(ctx context.Context) error {
-	path := path.Join("/api/v3/experiments", url.PathEscape(h.ref), "stop")
+	ctx = withOperation(ctx, "ExperimentHandle.Stop")
+
+	defer h.client.cache.invalidate(experimentKind, h.ref)
+	path, err := h.path(ctx, "stop")
+	if err != nil {
+		return err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPut, path, nil, nil)
 	if err != nil {
 		return err
@@ -138,7 +217,13 @@ func (h *ExperimentHandle) Stop(ctx context.Context) error {
 
 // Delete an experiment. This action is not reversible.
 func (h *ExperimentHandle) Delete(ctx context.Context) error {
-	path := path.Join("/api/v3/experiments", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "ExperimentHandle.Delete")
+
+	defer h.client.cache.invalidate(experimentKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodDelete, path, nil, nil)
 	if err != nil {
 		return err
@@ -149,7 +234,12 @@ func (h *ExperimentHandle) Delete(ctx context.Context) error {
 
 // Tasks of the experiment
 func (h *ExperimentHandle) Tasks(ctx context.Context) ([]api.Task, error) {
-	path := path.Join("/api/v3/experiments", url.PathEscape(h.ref), "tasks")
+	ctx = withOperation(ctx, "ExperimentHandle.Tasks")
+
+	path, err := h.path(ctx, "tasks")
+	if err != nil {
+		return nil, err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, err
@@ -163,11 +253,210 @@ func (h *ExperimentHandle) Tasks(ctx context.Context) ([]api.Task, error) {
 	return tasks, nil
 }
 
+// ErrTaskFailed indicates a task did not complete successfully.
+var ErrTaskFailed = errors.New("task failed")
+
+// ErrNoTasks indicates an experiment has no tasks to wait for.
+var ErrNoTasks = errors.New("experiment has no tasks")
+
+// WaitOptions configures how ExperimentHandle.Wait polls for completion.
+// All fields are optional.
+type WaitOptions struct {
+	// PollInterval is the time to wait between the first status checks.
+	// Defaults to 1 second. The interval doubles after each check, up to
+	// MaxPollInterval.
+	PollInterval time.Duration
+
+	// MaxPollInterval is the longest time to wait between status checks.
+	// Defaults to 30 seconds.
+	MaxPollInterval time.Duration
+
+	// Timeout bounds the total time to wait. If zero, Wait returns only when
+	// the experiment completes or the context is done.
+	Timeout time.Duration
+
+	// FailFast returns as soon as any task fails instead of waiting for all
+	// tasks to complete.
+	FailFast bool
+
+	// Progress is called with the experiment's details after each status check.
+	Progress func(*api.Experiment)
+}
+
+// TaskResult describes the final state of a task's most recent execution.
+type TaskResult struct {
+	// Identity
+	Task      string
+	Name      string
+	Execution string
+
+	// Finalized is set once the task has ended and all results are captured.
+	Finalized bool
+
+	// Failed is set if the task ended abnormally.
+	Failed bool
+
+	// Canceled is set if the task was stopped before completing.
+	Canceled bool
+
+	// ExitCode is the process exit code, if the task exited normally.
+	ExitCode *int
+
+	// Message describes additional state-related context.
+	Message string
+}
+
+// Succeeded returns whether a task was finalized after exiting with code 0.
+func (r TaskResult) Succeeded() bool {
+	return r.Finalized && !r.Failed && !r.Canceled && r.ExitCode != nil && *r.ExitCode == 0
+}
+
+// failed returns whether a task has ended, or will end, unsuccessfully.
+func (r TaskResult) failed() bool {
+	if r.Finalized {
+		return !r.Succeeded()
+	}
+	return r.Failed || r.Canceled || (r.ExitCode != nil && *r.ExitCode != 0)
+}
+
+// Wait blocks until all of an experiment's tasks are finalized, returning the
+// final state of each task. If any task fails, results are returned with an
+// error wrapping ErrTaskFailed. If the experiment has no tasks, Wait returns
+// ErrNoTasks immediately.
+//
+// If the timeout expires or the context is done first, Wait returns the state
+// of each task as of the last status check along with the context's error.
+//
+// If opts.FailFast is set, Wait returns as soon as any task fails; tasks that
+// are still running are left as is and may be stopped with Stop.
+func (h *ExperimentHandle) Wait(ctx context.Context, opts *WaitOptions) ([]TaskResult, error) {
+	ctx = withOperation(ctx, "ExperimentHandle.Wait")
+
+	var o WaitOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+	if o.MaxPollInterval <= 0 {
+		o.MaxPollInterval = 30 * time.Second
+	}
+	if o.MaxPollInterval < o.PollInterval {
+		o.MaxPollInterval = o.PollInterval
+	}
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	interval := o.PollInterval
+	var results []TaskResult
+	for {
+		experiment, err := h.get(ctx)
+		if err != nil {
+			return results, err
+		}
+		if o.Progress != nil {
+			o.Progress(experiment)
+		}
+
+		results = taskResults(experiment)
+		if len(results) == 0 {
+			return nil, ErrNoTasks
+		}
+
+		done := true
+		var failed *TaskResult
+		for i := range results {
+			if !results[i].Finalized {
+				done = false
+			}
+			if failed == nil && results[i].failed() {
+				failed = &results[i]
+			}
+		}
+
+		if failed != nil && (done || o.FailFast) {
+			return results, fmt.Errorf("%s: %w", describeTask(failed), ErrTaskFailed)
+		}
+		if done {
+			return results, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return results, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		if interval *= 2; interval > o.MaxPollInterval {
+			interval = o.MaxPollInterval
+		}
+	}
+}
+
+// taskResults summarizes the most recent execution of each task in an
+// experiment, in the order in which tasks first appear.
+func taskResults(experiment *api.Experiment) []TaskResult {
+	var results []TaskResult
+	latest := map[string]int{} // Task ID to index in results.
+	created := map[string]time.Time{}
+	for _, e := range experiment.Executions {
+		if e == nil {
+			continue
+		}
+
+		r := TaskResult{
+			Task:      e.Task,
+			Name:      e.Spec.Name,
+			Execution: e.ID,
+			Finalized: e.State.Finalized != nil,
+			Failed:    e.State.Failed != nil,
+			Canceled:  e.State.Canceled != nil,
+			ExitCode:  e.State.ExitCode,
+			Message:   e.State.Message,
+		}
+
+		i, ok := latest[e.Task]
+		if !ok {
+			latest[e.Task] = len(results)
+			created[e.Task] = e.State.Created
+			results = append(results, r)
+		} else if !e.State.Created.Before(created[e.Task]) {
+			created[e.Task] = e.State.Created
+			results[i] = r
+		}
+	}
+	return results
+}
+
+func describeTask(r *TaskResult) string {
+	name := r.Name
+	if name == "" {
+		name = r.Task
+	}
+
+	switch {
+	case r.Canceled:
+		return fmt.Sprintf("task %s was canceled", name)
+	case r.ExitCode != nil && *r.ExitCode != 0:
+		return fmt.Sprintf("task %s exited with code %d", name, *r.ExitCode)
+	case r.Message != "":
+		return fmt.Sprintf("task %s failed: %s", name, r.Message)
+	default:
+		return fmt.Sprintf("task %s failed", name)
+	}
+}
+
 func (c *Client) SearchExperiments(
 	ctx context.Context,
 	searchOptions api.ExperimentSearchOptions,
 	page int,
 ) ([]api.Experiment, error) {
+	ctx = withOperation(ctx, "Client.SearchExperiments")
+
 	query := url.Values{"page": {strconv.Itoa(page)}}
 	resp, err := c.sendRetryableRequest(ctx, http.MethodPost, "/api/v3/experiments/search", query, searchOptions)
 	if err != nil {
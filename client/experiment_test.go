@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beaker/client/api"
+)
+
+// experimentStates serves each experiment in turn on successive requests,
+// repeating the last one indefinitely.
+func experimentStates(t *testing.T, states ...api.Experiment) (*Client, func() int) {
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		i := calls
+		calls++
+		mu.Unlock()
+
+		if i >= len(states) {
+			i = len(states) - 1
+		}
+		_ = json.NewEncoder(w).Encode(states[i])
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, "")
+	require.NoError(t, err)
+	return client, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls
+	}
+}
+
+func execution(task string, state api.ExecutionState) *api.Execution {
+	return &api.Execution{ID: "e-" + task, Task: task, Spec: api.TaskSpecV2{Name: task}, State: state}
+}
+
+func TestWait(t *testing.T) {
+	now := time.Now()
+	zero, one := 0, 1
+	running := api.ExecutionState{Created: now, Started: &now}
+	succeeded := api.ExecutionState{Created: now, ExitCode: &zero, Finalized: &now}
+	failed := api.ExecutionState{Created: now, ExitCode: &one, Finalized: &now}
+	fast := &WaitOptions{PollInterval: time.Millisecond}
+
+	t.Run("Success", func(t *testing.T) {
+		client, calls := experimentStates(t,
+			api.Experiment{ID: "x", Executions: []*api.Execution{execution("a", running)}},
+			api.Experiment{ID: "x", Executions: []*api.Execution{execution("a", succeeded)}},
+		)
+
+		var progress []int
+		opts := *fast
+		opts.Progress = func(e *api.Experiment) { progress = append(progress, len(e.Executions)) }
+
+		results, err := client.Experiment("x").Wait(context.Background(), &opts)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Succeeded())
+		assert.Equal(t, []int{1, 1}, progress)
+		assert.Equal(t, 2, calls())
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		states := make([]api.Experiment, 6)
+		for i := range states {
+			states[i] = api.Experiment{ID: "x", Executions: []*api.Execution{execution("a", running)}}
+		}
+		states[5].Executions[0] = execution("a", succeeded)
+		client, _ := experimentStates(t, states...)
+
+		var polls []time.Time
+		opts := WaitOptions{
+			PollInterval:    10 * time.Millisecond,
+			MaxPollInterval: 20 * time.Millisecond,
+			Progress:        func(*api.Experiment) { polls = append(polls, time.Now()) },
+		}
+		_, err := client.Experiment("x").Wait(context.Background(), &opts)
+		require.NoError(t, err)
+		require.Len(t, polls, 6)
+
+		// The interval doubles after each check until it reaches the maximum.
+		for i, min := range []time.Duration{10, 20, 20, 20, 20} {
+			gap := polls[i+1].Sub(polls[i])
+			assert.GreaterOrEqual(t, int64(gap), int64(min*time.Millisecond), "poll %d", i+1)
+		}
+		assert.Less(t, int64(polls[5].Sub(polls[4])), int64(150*time.Millisecond),
+			"the interval should be capped")
+	})
+
+	t.Run("TaskFailed", func(t *testing.T) {
+		client, _ := experimentStates(t, api.Experiment{ID: "x", Executions: []*api.Execution{
+			execution("a", succeeded),
+			execution("b", failed),
+		}})
+
+		results, err := client.Experiment("x").Wait(context.Background(), fast)
+		assert.ErrorIs(t, err, ErrTaskFailed)
+		assert.Contains(t, err.Error(), "task b exited with code 1")
+		require.Len(t, results, 2)
+		assert.True(t, results[0].Succeeded())
+		assert.False(t, results[1].Succeeded())
+	})
+
+	t.Run("FailFast", func(t *testing.T) {
+		exited := api.ExecutionState{Created: now, ExitCode: &one}
+		client, calls := experimentStates(t, api.Experiment{ID: "x", Executions: []*api.Execution{
+			execution("a", running),
+			execution("b", exited),
+		}})
+
+		opts := *fast
+		opts.FailFast = true
+		results, err := client.Experiment("x").Wait(context.Background(), &opts)
+		assert.ErrorIs(t, err, ErrTaskFailed)
+		require.Len(t, results, 2)
+		assert.False(t, results[0].Finalized)
+		assert.Equal(t, 1, calls())
+
+		// Without FailFast, Wait keeps polling until every task is finalized.
+		opts.FailFast = false
+		opts.Timeout = 20 * time.Millisecond
+		_, err = client.Experiment("x").Wait(context.Background(), &opts)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		client, _ := experimentStates(t, api.Experiment{ID: "x", Executions: []*api.Execution{
+			execution("a", succeeded),
+			execution("b", running),
+		}})
+
+		opts := *fast
+		opts.Timeout = 20 * time.Millisecond
+		results, err := client.Experiment("x").Wait(context.Background(), &opts)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Len(t, results, 2, "results should reflect the last status check")
+		assert.True(t, results[0].Succeeded())
+		assert.False(t, results[1].Finalized)
+	})
+
+	t.Run("NoTasks", func(t *testing.T) {
+		client, calls := experimentStates(t, api.Experiment{ID: "x"})
+
+		results, err := client.Experiment("x").Wait(context.Background(), fast)
+		assert.Equal(t, ErrNoTasks, err)
+		assert.Empty(t, results)
+		assert.Equal(t, 1, calls())
+	})
+}
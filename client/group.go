@@ -11,6 +11,12 @@ import (
 
 // CreateGroup creates a new group with an optional name.
 func (c *Client) CreateGroup(ctx context.Context, spec api.GroupSpec) (*GroupHandle, error) {
+	ctx = withOperation(ctx, "Client.CreateGroup")
+
+	if spec.Workspace == "" {
+		spec.Workspace = c.workspaceRef()
+	}
+
 	resp, err := c.sendRetryableRequest(ctx, http.MethodPost, "/api/v3/groups", nil, spec)
 	if err != nil {
 		return nil, err
@@ -40,9 +46,28 @@ func (h *GroupHandle) Ref() string {
 	return h.ref
 }
 
+// path returns the API path of the group, or of elem within it.
+func (h *GroupHandle) path(ctx context.Context, elem ...string) (string, error) {
+	ref, err := h.client.resolveItem(ctx, groupKind, h.ref)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(append([]string{"/api/v3/groups", url.PathEscape(ref)}, elem...)...), nil
+}
+
 // Get retrieves a group's details.
 func (h *GroupHandle) Get(ctx context.Context) (*api.Group, error) {
-	path := path.Join("/api/v3/groups", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "GroupHandle.Get")
+
+	var cached api.Group
+	if h.client.cache.get(groupKind, h.ref, &cached) {
+		return &cached, nil
+	}
+
+	path, err := h.path(ctx)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, err
@@ -53,12 +78,36 @@ func (h *GroupHandle) Get(ctx context.Context) (*api.Group, error) {
 	if err = parseResponse(resp, &body); err != nil {
 		return nil, err
 	}
+
+	h.client.cache.put(groupKind, body.ID, &body, h.ref, body.FullName)
 	return &body, nil
 }
 
+// Resolve returns a handle pinned to the group's ID, so it continues to
+// refer to the same group if its name is changed or reused.
+func (h *GroupHandle) Resolve(ctx context.Context) (*GroupHandle, error) {
+	ctx = withOperation(ctx, "GroupHandle.Resolve")
+
+	if isID(h.ref) {
+		return h, nil
+	}
+
+	group, err := h.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &GroupHandle{client: h.client, ref: group.ID}, nil
+}
+
 // SetName sets a group's name.
 func (h *GroupHandle) SetName(ctx context.Context, name string) error {
-	path := path.Join("/api/v3/groups", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "GroupHandle.SetName")
+
+	defer h.client.cache.invalidate(groupKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.GroupPatchSpec{Name: &name}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -70,7 +119,13 @@ func (h *GroupHandle) SetName(ctx context.Context, name string) error {
 
 // SetDescription sets a group's description.
 func (h *GroupHandle) SetDescription(ctx context.Context, description string) error {
-	path := path.Join("/api/v3/groups", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "GroupHandle.SetDescription")
+
+	defer h.client.cache.invalidate(groupKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.GroupPatchSpec{Description: &description}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -82,7 +137,12 @@ func (h *GroupHandle) SetDescription(ctx context.Context, description string) er
 
 // Experiments returns the IDs of all experiments within a group.
 func (h *GroupHandle) Experiments(ctx context.Context) ([]string, error) {
-	path := path.Join("/api/v3/groups", url.PathEscape(h.ref), "experiments")
+	ctx = withOperation(ctx, "GroupHandle.Experiments")
+
+	path, err := h.path(ctx, "experiments")
+	if err != nil {
+		return nil, err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, err
@@ -98,11 +158,17 @@ func (h *GroupHandle) Experiments(ctx context.Context) ([]string, error) {
 
 // AddExperiments adds experiments by name or ID to a group.
 func (h *GroupHandle) AddExperiments(ctx context.Context, experiments []string) error {
+	ctx = withOperation(ctx, "GroupHandle.AddExperiments")
+
+	defer h.client.cache.invalidate(groupKind, h.ref)
 	if len(experiments) == 0 {
 		return nil
 	}
 
-	path := path.Join("/api/v3/groups", url.PathEscape(h.ref))
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.GroupPatchSpec{AddExperiments: experiments}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -115,11 +181,17 @@ func (h *GroupHandle) AddExperiments(ctx context.Context, experiments []string)
 
 // RemoveExperiments removes experiments by name or ID from a group.
 func (h *GroupHandle) RemoveExperiments(ctx context.Context, experiments []string) error {
+	ctx = withOperation(ctx, "GroupHandle.RemoveExperiments")
+
+	defer h.client.cache.invalidate(groupKind, h.ref)
 	if len(experiments) == 0 {
 		return nil
 	}
 
-	path := path.Join("/api/v3/groups", url.PathEscape(h.ref))
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.GroupPatchSpec{RemoveExperiments: experiments}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -132,7 +204,13 @@ func (h *GroupHandle) RemoveExperiments(ctx context.Context, experiments []strin
 
 // Delete removes a group and its contents.
 func (h *GroupHandle) Delete(ctx context.Context) error {
-	path := path.Join("/api/v3/groups", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "GroupHandle.Delete")
+
+	defer h.client.cache.invalidate(groupKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodDelete, path, nil, nil)
 	if err != nil {
 		return err
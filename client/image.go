@@ -16,6 +16,12 @@ func (c *Client) CreateImage(
 	spec api.ImageSpec,
 	name string,
 ) (*ImageHandle, error) {
+	ctx = withOperation(ctx, "Client.CreateImage")
+
+	if spec.Workspace == "" {
+		spec.Workspace = c.workspaceRef()
+	}
+
 	query := url.Values{}
 	if name != "" {
 		query.Set("name", name)
@@ -51,9 +57,28 @@ func (h *ImageHandle) Ref() string {
 	return h.ref
 }
 
+// path returns the API path of the image, or of elem within it.
+func (h *ImageHandle) path(ctx context.Context, elem ...string) (string, error) {
+	ref, err := h.client.resolveItem(ctx, imageKind, h.ref)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(append([]string{"/api/v3/images", url.PathEscape(ref)}, elem...)...), nil
+}
+
 // Get retrieves an image's details.
 func (h *ImageHandle) Get(ctx context.Context) (*api.Image, error) {
-	uri := path.Join("/api/v3/images", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "ImageHandle.Get")
+
+	var cached api.Image
+	if h.client.cache.get(imageKind, h.ref, &cached) {
+		return &cached, nil
+	}
+
+	uri, err := h.path(ctx)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, uri, nil, nil)
 	if err != nil {
 		return nil, err
@@ -64,15 +89,38 @@ func (h *ImageHandle) Get(ctx context.Context) (*api.Image, error) {
 	if err := parseResponse(resp, &body); err != nil {
 		return nil, err
 	}
+
+	h.client.cache.put(imageKind, body.ID, &body, h.ref, body.FullName)
 	return &body, nil
 }
 
+// Resolve returns a handle pinned to the image's ID, so it continues to
+// refer to the same image if its name is changed or reused.
+func (h *ImageHandle) Resolve(ctx context.Context) (*ImageHandle, error) {
+	ctx = withOperation(ctx, "ImageHandle.Resolve")
+
+	if isID(h.ref) {
+		return h, nil
+	}
+
+	image, err := h.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ImageHandle{client: h.client, ref: image.ID}, nil
+}
+
 // Repository returns information required to access an image through Docker.
 func (h *ImageHandle) Repository(
 	ctx context.Context,
 	upload bool,
 ) (*api.ImageRepository, error) {
-	path := path.Join("/api/v3/images", url.PathEscape(h.ref), "repository")
+	ctx = withOperation(ctx, "ImageHandle.Repository")
+
+	path, err := h.path(ctx, "repository")
+	if err != nil {
+		return nil, err
+	}
 	query := url.Values{"upload": {strconv.FormatBool(upload)}}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, query, nil)
 	if err != nil {
@@ -89,7 +137,13 @@ func (h *ImageHandle) Repository(
 
 // SetName sets an image's name.
 func (h *ImageHandle) SetName(ctx context.Context, name string) error {
-	path := path.Join("/api/v3/images", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "ImageHandle.SetName")
+
+	defer h.client.cache.invalidate(imageKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.ImagePatchSpec{Name: &name}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -101,7 +155,13 @@ func (h *ImageHandle) SetName(ctx context.Context, name string) error {
 
 // SetDescription sets an image's description.
 func (h *ImageHandle) SetDescription(ctx context.Context, description string) error {
-	path := path.Join("/api/v3/images", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "ImageHandle.SetDescription")
+
+	defer h.client.cache.invalidate(imageKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.ImagePatchSpec{Description: &description}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -114,7 +174,13 @@ func (h *ImageHandle) SetDescription(ctx context.Context, description string) er
 // Commit finalizes an image, unblocking usage and locking it for further
 // writes. The image is guaranteed to remain uncommitted on failure.
 func (h *ImageHandle) Commit(ctx context.Context) error {
-	path := path.Join("/api/v3/images", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "ImageHandle.Commit")
+
+	defer h.client.cache.invalidate(imageKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	body := api.ImagePatchSpec{Commit: true}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, body)
 	if err != nil {
@@ -126,7 +192,13 @@ func (h *ImageHandle) Commit(ctx context.Context) error {
 
 // Delete an image. Note that this action is not reversible.
 func (h *ImageHandle) Delete(ctx context.Context) error {
-	path := path.Join("/api/v3/images", url.PathEscape(h.ref))
+	ctx = withOperation(ctx, "ImageHandle.Delete")
+
+	defer h.client.cache.invalidate(imageKind, h.ref)
+	path, err := h.path(ctx)
+	if err != nil {
+		return err
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodDelete, path, nil, nil)
 	if err != nil {
 		return err
@@ -140,6 +212,8 @@ func (c *Client) SearchImages(
 	searchOptions api.ImageSearchOptions,
 	page int,
 ) ([]api.Image, error) {
+	ctx = withOperation(ctx, "Client.SearchImages")
+
 	query := url.Values{"page": {strconv.Itoa(page)}}
 	resp, err := c.sendRetryableRequest(ctx, http.MethodPost, "/api/v3/images/search", query, searchOptions)
 	if err != nil {
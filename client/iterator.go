@@ -0,0 +1,431 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/beaker/client/api"
+)
+
+// IteratorOptions bounds how an iterator walks a paginated collection.
+// All fields are optional.
+type IteratorOptions struct {
+	// PageSize is the maximum number of items to request at once. If zero, the
+	// service's default page size is used.
+	PageSize int
+
+	// MaxItems stops iteration after the given number of items. If zero, all
+	// items are returned.
+	MaxItems int
+}
+
+// pageFunc fetches a single page starting at cursor, requesting at most limit
+// items. A limit of zero requests the service's default page size. It returns
+// the number of items fetched and the cursor of the following page, which is
+// empty when no more pages exist.
+type pageFunc func(ctx context.Context, cursor string, limit int) (int, string, error)
+
+// pageLimits returns limits with the page size defaulted to limit, so an
+// iterator honors the Limit of its list options unless a page size is set.
+func pageLimits(limits *IteratorOptions, limit int64) *IteratorOptions {
+	var l IteratorOptions
+	if limits != nil {
+		l = *limits
+	}
+	if l.PageSize == 0 {
+		l.PageSize = int(limit)
+	}
+	return &l
+}
+
+// maxEmptyPages is the number of consecutive empty pages after which an
+// iterator gives up, in case the service never reports the end of a collection.
+const maxEmptyPages = 10
+
+// pager walks a cursor-paginated collection. Typed iterators embed a pager
+// and expose the item at the current index.
+type pager struct {
+	ctx   context.Context
+	opts  IteratorOptions
+	fetch pageFunc
+
+	cursor  string
+	fetched bool // Whether at least one page has been requested.
+	index   int  // Index of the current item within the current page.
+	size    int  // Number of items in the current page.
+	count   int  // Number of items yielded so far.
+	empty   int  // Number of consecutive empty pages.
+	done    bool
+	err     error
+}
+
+func newPager(ctx context.Context, cursor string, opts *IteratorOptions, fetch pageFunc) pager {
+	p := pager{ctx: ctx, fetch: fetch, cursor: cursor, index: -1}
+	if opts != nil {
+		p.opts = *opts
+	}
+	return p
+}
+
+// Next advances the iterator to the next item, fetching a new page if
+// necessary. It returns false when iteration is complete or an error occurs,
+// after which Err reports the error, if any. It's an error for the service to
+// return many consecutive empty pages.
+func (p *pager) Next() bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	if p.opts.MaxItems > 0 && p.count >= p.opts.MaxItems {
+		p.done = true
+		return false
+	}
+
+	p.index++
+	for p.index >= p.size {
+		if p.fetched && p.cursor == "" {
+			p.done = true
+			return false
+		}
+		if err := p.ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+
+		limit := p.opts.PageSize
+		if p.opts.MaxItems > 0 {
+			if remaining := p.opts.MaxItems - p.count; limit == 0 || remaining < limit {
+				limit = remaining
+			}
+		}
+
+		n, next, err := p.fetch(p.ctx, p.cursor, limit)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		p.fetched = true
+
+		// An empty page which returns its own cursor can never make progress.
+		if n == 0 && next == p.cursor {
+			p.done = true
+			return false
+		}
+		if n == 0 {
+			if p.empty++; p.empty >= maxEmptyPages {
+				p.err = fmt.Errorf("no items returned in %d consecutive pages", maxEmptyPages)
+				return false
+			}
+		} else {
+			p.empty = 0
+		}
+
+		p.cursor = next
+		p.index, p.size = 0, n
+	}
+
+	p.count++
+	return true
+}
+
+// Err returns the first error encountered during iteration, if any.
+func (p *pager) Err() error {
+	return p.err
+}
+
+// WorkspaceIterator iterates over workspaces.
+type WorkspaceIterator struct {
+	pager
+	page []api.Workspace
+}
+
+// Value returns the current workspace. It is only valid after Next returns true.
+func (it *WorkspaceIterator) Value() api.Workspace {
+	return it.page[it.index]
+}
+
+// IterateWorkspaces returns an iterator over all workspaces in an organization.
+// If set, opts.Cursor determines where iteration begins. The page size in
+// limits, if set, takes precedence over opts.Limit.
+func (c *Client) IterateWorkspaces(
+	ctx context.Context,
+	org string,
+	opts *ListWorkspaceOptions,
+	limits *IteratorOptions,
+) *WorkspaceIterator {
+	ctx = withOperation(ctx, "Client.IterateWorkspaces")
+
+	var o ListWorkspaceOptions
+	if opts != nil {
+		o = *opts
+	}
+	limits = pageLimits(limits, o.Limit)
+
+	it := &WorkspaceIterator{}
+	it.pager = newPager(ctx, o.Cursor, limits, func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		o.Cursor, o.Limit = cursor, int64(limit)
+		page, next, err := c.ListWorkspaces(ctx, org, &o)
+		it.page = page
+		return len(page), next, err
+	})
+	return it
+}
+
+// DatasetIterator iterates over datasets.
+type DatasetIterator struct {
+	pager
+	page []api.Dataset
+}
+
+// Value returns the current dataset. It is only valid after Next returns true.
+func (it *DatasetIterator) Value() api.Dataset {
+	return it.page[it.index]
+}
+
+// IterateDatasets returns an iterator over all datasets in a workspace.
+// If set, opts.Cursor determines where iteration begins. The page size in
+// limits, if set, takes precedence over opts.Limit.
+func (h *WorkspaceHandle) IterateDatasets(
+	ctx context.Context,
+	opts *ListDatasetOptions,
+	limits *IteratorOptions,
+) *DatasetIterator {
+	ctx = withOperation(ctx, "WorkspaceHandle.IterateDatasets")
+
+	var o ListDatasetOptions
+	if opts != nil {
+		o = *opts
+	}
+	limits = pageLimits(limits, o.Limit)
+
+	it := &DatasetIterator{}
+	it.pager = newPager(ctx, o.Cursor, limits, func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		o.Cursor, o.Limit = cursor, int64(limit)
+		page, next, err := h.Datasets(ctx, &o)
+		it.page = page
+		return len(page), next, err
+	})
+	return it
+}
+
+// ExperimentIterator iterates over experiments.
+type ExperimentIterator struct {
+	pager
+	page []api.Experiment
+}
+
+// Value returns the current experiment. It is only valid after Next returns true.
+func (it *ExperimentIterator) Value() api.Experiment {
+	return it.page[it.index]
+}
+
+// IterateExperiments returns an iterator over all experiments in a workspace.
+// If set, opts.Cursor determines where iteration begins. The page size in
+// limits, if set, takes precedence over opts.Limit.
+func (h *WorkspaceHandle) IterateExperiments(
+	ctx context.Context,
+	opts *ListExperimentOptions,
+	limits *IteratorOptions,
+) *ExperimentIterator {
+	ctx = withOperation(ctx, "WorkspaceHandle.IterateExperiments")
+
+	var o ListExperimentOptions
+	if opts != nil {
+		o = *opts
+	}
+	limits = pageLimits(limits, o.Limit)
+
+	it := &ExperimentIterator{}
+	it.pager = newPager(ctx, o.Cursor, limits, func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		o.Cursor, o.Limit = cursor, int64(limit)
+		page, next, err := h.Experiments(ctx, &o)
+		it.page = page
+		return len(page), next, err
+	})
+	return it
+}
+
+// GroupIterator iterates over groups.
+type GroupIterator struct {
+	pager
+	page []api.Group
+}
+
+// Value returns the current group. It is only valid after Next returns true.
+func (it *GroupIterator) Value() api.Group {
+	return it.page[it.index]
+}
+
+// IterateGroups returns an iterator over all groups in a workspace.
+// If set, opts.Cursor determines where iteration begins. The page size in
+// limits, if set, takes precedence over opts.Limit.
+func (h *WorkspaceHandle) IterateGroups(
+	ctx context.Context,
+	opts *ListGroupOptions,
+	limits *IteratorOptions,
+) *GroupIterator {
+	ctx = withOperation(ctx, "WorkspaceHandle.IterateGroups")
+
+	var o ListGroupOptions
+	if opts != nil {
+		o = *opts
+	}
+	limits = pageLimits(limits, o.Limit)
+
+	it := &GroupIterator{}
+	it.pager = newPager(ctx, o.Cursor, limits, func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		o.Cursor, o.Limit = cursor, int64(limit)
+		page, next, err := h.Groups(ctx, &o)
+		it.page = page
+		return len(page), next, err
+	})
+	return it
+}
+
+// ImageIterator iterates over images.
+type ImageIterator struct {
+	pager
+	page []api.Image
+}
+
+// Value returns the current image. It is only valid after Next returns true.
+func (it *ImageIterator) Value() api.Image {
+	return it.page[it.index]
+}
+
+// IterateImages returns an iterator over all images in a workspace.
+// If set, opts.Cursor determines where iteration begins. The page size in
+// limits, if set, takes precedence over opts.Limit.
+func (h *WorkspaceHandle) IterateImages(
+	ctx context.Context,
+	opts *ListImageOptions,
+	limits *IteratorOptions,
+) *ImageIterator {
+	ctx = withOperation(ctx, "WorkspaceHandle.IterateImages")
+
+	var o ListImageOptions
+	if opts != nil {
+		o = *opts
+	}
+	limits = pageLimits(limits, o.Limit)
+
+	it := &ImageIterator{}
+	it.pager = newPager(ctx, o.Cursor, limits, func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		o.Cursor, o.Limit = cursor, int64(limit)
+		page, next, err := h.Images(ctx, &o)
+		it.page = page
+		return len(page), next, err
+	})
+	return it
+}
+
+// ClusterIterator iterates over clusters.
+type ClusterIterator struct {
+	pager
+	page []api.Cluster
+}
+
+// Value returns the current cluster. It is only valid after Next returns true.
+func (it *ClusterIterator) Value() api.Cluster {
+	return it.page[it.index]
+}
+
+// IterateClusters returns an iterator over all clusters owned by an account.
+// If set, opts.Cursor determines where iteration begins. The page size in
+// limits, if set, takes precedence over opts.Limit.
+func (c *Client) IterateClusters(
+	ctx context.Context,
+	account string,
+	opts *ListClusterOptions,
+	limits *IteratorOptions,
+) *ClusterIterator {
+	ctx = withOperation(ctx, "Client.IterateClusters")
+
+	var o ListClusterOptions
+	if opts != nil {
+		o = *opts
+	}
+	limits = pageLimits(limits, o.Limit)
+
+	it := &ClusterIterator{}
+	it.pager = newPager(ctx, o.Cursor, limits, func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		o.Cursor, o.Limit = cursor, int64(limit)
+		page, next, err := c.ListClusters(ctx, account, &o)
+		it.page = page
+		return len(page), next, err
+	})
+	return it
+}
+
+// UserIterator iterates over users.
+type UserIterator struct {
+	pager
+	page []api.UserDetail
+}
+
+// Value returns the current user. It is only valid after Next returns true.
+func (it *UserIterator) Value() api.UserDetail {
+	return it.page[it.index]
+}
+
+// IterateUsers returns an iterator over all users, starting at cursor.
+func (c *Client) IterateUsers(
+	ctx context.Context,
+	cursor string,
+	limits *IteratorOptions,
+) *UserIterator {
+	ctx = withOperation(ctx, "Client.IterateUsers")
+
+	it := &UserIterator{}
+	it.pager = newPager(ctx, cursor, limits, func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		page, next, err := c.listUsers(ctx, cursor, limit)
+		it.page = page
+		return len(page), next, err
+	})
+	return it
+}
+
+// IterateMembers returns an iterator over all members of an organization,
+// starting at cursor.
+func (h *OrgHandle) IterateMembers(
+	ctx context.Context,
+	cursor string,
+	limits *IteratorOptions,
+) *UserIterator {
+	ctx = withOperation(ctx, "OrgHandle.IterateMembers")
+
+	it := &UserIterator{}
+	it.pager = newPager(ctx, cursor, limits, func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		page, next, err := h.listMembers(ctx, cursor, limit)
+		it.page = page
+		return len(page), next, err
+	})
+	return it
+}
+
+// OrganizationIterator iterates over organizations.
+type OrganizationIterator struct {
+	pager
+	page []api.Organization
+}
+
+// Value returns the current organization. It is only valid after Next returns true.
+func (it *OrganizationIterator) Value() api.Organization {
+	return it.page[it.index]
+}
+
+// IterateOrganizations returns an iterator over all organizations, starting at cursor.
+func (c *Client) IterateOrganizations(
+	ctx context.Context,
+	cursor string,
+	limits *IteratorOptions,
+) *OrganizationIterator {
+	ctx = withOperation(ctx, "Client.IterateOrganizations")
+
+	it := &OrganizationIterator{}
+	it.pager = newPager(ctx, cursor, limits, func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		page, next, err := c.listOrganizations(ctx, cursor, limit)
+		it.page = page
+		return len(page), next, err
+	})
+	return it
+}
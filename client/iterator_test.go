@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// intPages serves the integers [0, total) in pages of the requested size,
+// defaulting to a page size of 3.
+func intPages(total int, items *[]int, limits *[]int) pageFunc {
+	return func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		*limits = append(*limits, limit)
+		start := 0
+		if cursor != "" {
+			start, _ = strconv.Atoi(cursor)
+		}
+		size := limit
+		if size == 0 {
+			size = 3
+		}
+		end := start + size
+		if end > total {
+			end = total
+		}
+
+		*items = (*items)[:0]
+		for i := start; i < end; i++ {
+			*items = append(*items, i)
+		}
+
+		next := ""
+		if end < total {
+			next = strconv.Itoa(end)
+		}
+		return end - start, next, nil
+	}
+}
+
+func TestPager(t *testing.T) {
+	cases := map[string]struct {
+		total    int
+		cursor   string
+		opts     *IteratorOptions
+		expected []int
+		limits   []int
+	}{
+		"Empty": {
+			total:    0,
+			expected: nil,
+			limits:   []int{0},
+		},
+		"AllPages": {
+			total:    7,
+			expected: []int{0, 1, 2, 3, 4, 5, 6},
+			limits:   []int{0, 0, 0},
+		},
+		"StartCursor": {
+			total:    7,
+			cursor:   "5",
+			expected: []int{5, 6},
+			limits:   []int{0},
+		},
+		"PageSize": {
+			total:    5,
+			opts:     &IteratorOptions{PageSize: 2},
+			expected: []int{0, 1, 2, 3, 4},
+			limits:   []int{2, 2, 2},
+		},
+		"MaxItems": {
+			total:    10,
+			opts:     &IteratorOptions{MaxItems: 4},
+			expected: []int{0, 1, 2, 3},
+			limits:   []int{4},
+		},
+		"PageSizeAndMaxItems": {
+			total:    10,
+			opts:     &IteratorOptions{PageSize: 3, MaxItems: 5},
+			expected: []int{0, 1, 2, 3, 4},
+			limits:   []int{3, 2},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var page, limits []int
+			p := newPager(context.Background(), c.cursor, c.opts, intPages(c.total, &page, &limits))
+
+			var actual []int
+			for p.Next() {
+				actual = append(actual, page[p.index])
+			}
+			require.NoError(t, p.Err())
+			assert.Equal(t, c.expected, actual)
+			assert.Equal(t, c.limits, limits)
+			assert.False(t, p.Next(), "exhausted iterators should stay exhausted")
+		})
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	expected := errors.New("failed")
+	calls := 0
+	p := newPager(context.Background(), "", nil, func(ctx context.Context, cursor string, limit int) (int, string, error) {
+		calls++
+		if calls > 1 {
+			return 0, "", expected
+		}
+		return 1, "next", nil
+	})
+
+	assert.True(t, p.Next())
+	assert.False(t, p.Next())
+	assert.Equal(t, expected, p.Err())
+	assert.False(t, p.Next())
+	assert.Equal(t, 2, calls)
+}
+
+func TestPagerCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var page, limits []int
+	p := newPager(ctx, "", nil, intPages(10, &page, &limits))
+
+	for i := 0; i < 3; i++ {
+		require.True(t, p.Next())
+	}
+	cancel()
+	assert.False(t, p.Next())
+	assert.Equal(t, context.Canceled, p.Err())
+}
+
+func TestIteratorPageSize(t *testing.T) {
+	var limits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		_, _ = w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		Limit    int64
+		Limits   *IteratorOptions
+		Expected string
+	}{
+		"Default":    {Expected: ""},
+		"OptsLimit":  {Limit: 5, Expected: "5"},
+		"PageSize":   {Limits: &IteratorOptions{PageSize: 2}, Expected: "2"},
+		"Precedence": {Limit: 5, Limits: &IteratorOptions{PageSize: 2}, Expected: "2"},
+		"MaxItems":   {Limit: 5, Limits: &IteratorOptions{MaxItems: 3}, Expected: "3"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			limits = nil
+			iterators := []interface {
+				Next() bool
+				Err() error
+			}{
+				client.IterateWorkspaces(ctx, "org", &ListWorkspaceOptions{Limit: c.Limit}, c.Limits),
+				client.Workspace("org/ws").IterateDatasets(ctx, &ListDatasetOptions{Limit: c.Limit}, c.Limits),
+				client.Workspace("org/ws").IterateExperiments(ctx, &ListExperimentOptions{Limit: c.Limit}, c.Limits),
+				client.Workspace("org/ws").IterateGroups(ctx, &ListGroupOptions{Limit: c.Limit}, c.Limits),
+				client.Workspace("org/ws").IterateImages(ctx, &ListImageOptions{Limit: c.Limit}, c.Limits),
+				client.IterateClusters(ctx, "org", &ListClusterOptions{Limit: c.Limit}, c.Limits),
+			}
+			for _, it := range iterators {
+				assert.False(t, it.Next())
+				require.NoError(t, it.Err())
+			}
+			assert.Equal(t, []string{c.Expected, c.Expected, c.Expected, c.Expected, c.Expected, c.Expected}, limits)
+		})
+	}
+}
+
+func TestIteratorEmptyPages(t *testing.T) {
+	cases := map[string]struct {
+		Next     func(cursor string) string
+		Requests int
+		Error    bool
+	}{
+		// The second request returns the cursor it was given.
+		"RepeatedCursor": {Next: func(string) string { return "same" }, Requests: 2},
+		"NewCursors":     {Next: func(cursor string) string { return cursor + "x" }, Requests: maxEmptyPages, Error: true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				next := c.Next(r.URL.Query().Get("cursor"))
+				_, _ = w.Write([]byte(`{"data": [], "nextCursor": "` + next + `"}`))
+			}))
+			defer server.Close()
+
+			client, err := NewClient(server.URL, "")
+			require.NoError(t, err)
+
+			it := client.IterateWorkspaces(context.Background(), "org", nil, nil)
+			assert.False(t, it.Next())
+			if c.Error {
+				assert.Error(t, it.Err())
+			} else {
+				assert.NoError(t, it.Err())
+			}
+			assert.Equal(t, c.Requests, requests)
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogRecord is a single line of an execution's logs.
+type LogRecord struct {
+	// Time at which the line was written.
+	Time time.Time
+
+	// Message is the content of the line, without a trailing newline.
+	Message string
+
+	// Task is the name, or ID if unnamed, of the task which wrote the line.
+	// It's only set when reading logs from an experiment.
+	Task string
+}
+
+// parseLogLine splits a log line of the form "{RFC3339 nano timestamp} {message}".
+// It returns false if the line doesn't begin with a timestamp.
+func parseLogLine(line string) (LogRecord, bool) {
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+
+	i := strings.IndexByte(line, ' ')
+	if i < 0 {
+		i = len(line)
+	}
+	t, err := time.Parse(time.RFC3339Nano, line[:i])
+	if err != nil {
+		return LogRecord{Message: line}, false
+	}
+
+	var message string
+	if i < len(line) {
+		message = line[i+1:]
+	}
+	return LogRecord{Time: t, Message: message}, true
+}
+
+// logScanner parses log records from a reader. Lines without a timestamp are
+// given the time of the line before them.
+type logScanner struct {
+	reader *bufio.Reader
+	last   time.Time
+
+	// continued is set if the last record scanned had no timestamp of its own.
+	continued bool
+}
+
+func newLogScanner(r io.Reader) *logScanner {
+	return &logScanner{reader: bufio.NewReader(r)}
+}
+
+// scan returns the next record, or io.EOF when the reader is exhausted.
+func (s *logScanner) scan() (LogRecord, error) {
+	line, err := s.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return LogRecord{}, err
+	}
+
+	record, ok := parseLogLine(line)
+	if ok {
+		s.last = record.Time
+	} else {
+		record.Time = s.last
+	}
+	s.continued = !ok
+	return record, nil
+}
+
+// LogFilter selects which log records to read. All fields are optional.
+type LogFilter struct {
+	// Since skips records written before the given time.
+	Since time.Time
+
+	// Until skips records written at or after the given time.
+	Until time.Time
+
+	// Contains selects records whose message contains the given substring.
+	Contains string
+
+	// Pattern selects records whose message matches the given expression.
+	Pattern *regexp.Regexp
+
+	// Head limits reading to the first N selected records.
+	Head int
+
+	// Tail limits reading to the last N selected records. If Head is also
+	// set, this selects the last N records of the head.
+	Tail int
+}
+
+func (f *LogFilter) match(r LogRecord) bool {
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if f.Contains != "" && !strings.Contains(r.Message, f.Contains) {
+		return false
+	}
+	if f.Pattern != nil && !f.Pattern.MatchString(r.Message) {
+		return false
+	}
+	return true
+}
+
+// logSource is a single stream of records within a LogReader.
+type logSource struct {
+	scanner *logScanner
+	task    string
+
+	next    LogRecord // Valid if pending is set.
+	pending bool
+	done    bool
+}
+
+// LogReader reads and filters parsed log records. When reading from multiple
+// executions, records are merged into a single stream ordered by time.
+type LogReader struct {
+	sources []*logSource
+	closers []io.Closer
+	filter  LogFilter
+
+	count   int // Number of records returned.
+	tailed  bool
+	tail    []LogRecord
+	current LogRecord
+	err     error
+}
+
+// NewLogReader creates a reader of logs in the form returned by
+// ExecutionHandle.GetLogs. If filter is nil, all records are read.
+func NewLogReader(r io.Reader, filter *LogFilter) *LogReader {
+	reader := &LogReader{sources: []*logSource{{scanner: newLogScanner(r)}}}
+	if filter != nil {
+		reader.filter = *filter
+	}
+	return reader
+}
+
+// Next advances to the next selected record. It returns false when all
+// records have been read or an error occurs, after which Err reports the
+// error, if any.
+func (r *LogReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	if r.filter.Tail > 0 {
+		if !r.tailed {
+			r.tailed = true
+			for r.nextMatch() {
+				r.tail = append(r.tail, r.current)
+				if len(r.tail) > r.filter.Tail {
+					r.tail = r.tail[1:]
+				}
+			}
+			if r.err != nil {
+				return false
+			}
+		}
+		if len(r.tail) == 0 {
+			return false
+		}
+		r.current, r.tail = r.tail[0], r.tail[1:]
+		return true
+	}
+
+	return r.nextMatch()
+}
+
+// nextMatch reads the next record which passes the filter.
+func (r *LogReader) nextMatch() bool {
+	if r.filter.Head > 0 && r.count >= r.filter.Head {
+		return false
+	}
+
+	for {
+		record, ok := r.merge()
+		if !ok {
+			return false
+		}
+
+		// Logs are ordered by time, so no later records can match.
+		if !r.filter.Until.IsZero() && !record.Time.Before(r.filter.Until) {
+			return false
+		}
+
+		if r.filter.match(record) {
+			r.current = record
+			r.count++
+			return true
+		}
+	}
+}
+
+// merge returns the earliest pending record among all sources.
+func (r *LogReader) merge() (LogRecord, bool) {
+	var earliest *logSource
+	for _, s := range r.sources {
+		if !s.pending && !s.done {
+			record, err := s.scanner.scan()
+			if err == io.EOF {
+				s.done = true
+				continue
+			}
+			if err != nil {
+				r.err = err
+				return LogRecord{}, false
+			}
+			record.Task = s.task
+			s.next, s.pending = record, true
+		}
+		if s.pending && (earliest == nil || s.next.Time.Before(earliest.next.Time)) {
+			earliest = s
+		}
+	}
+
+	if earliest == nil {
+		return LogRecord{}, false
+	}
+	earliest.pending = false
+	return earliest.next, true
+}
+
+// Value returns the current record. It is only valid after Next returns true.
+func (r *LogReader) Value() LogRecord {
+	return r.current
+}
+
+// Err returns the first error encountered while reading, if any.
+func (r *LogReader) Err() error {
+	return r.err
+}
+
+// Close releases any logs opened by the reader. Readers passed to
+// NewLogReader are not closed.
+func (r *LogReader) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	r.closers = nil
+	return err
+}
+
+// Logs reads an execution's logs. If filter is nil, all records are read.
+// The caller must close the returned reader.
+func (h *ExecutionHandle) Logs(ctx context.Context, filter *LogFilter) (*LogReader, error) {
+	ctx = withOperation(ctx, "ExecutionHandle.Logs")
+
+	var since time.Time
+	if filter != nil {
+		since = filter.Since
+	}
+
+	logs, err := h.getLogs(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewLogReader(logs, filter)
+	r.closers = []io.Closer{logs}
+	return r, nil
+}
+
+// Logs reads the logs of all of an experiment's executions, merged into a
+// single stream ordered by time. Each record is tagged with the name of the
+// task which wrote it. If filter is nil, all records are read.
+// The caller must close the returned reader.
+func (h *ExperimentHandle) Logs(ctx context.Context, filter *LogFilter) (*LogReader, error) {
+	ctx = withOperation(ctx, "ExperimentHandle.Logs")
+
+	experiment, err := h.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &LogReader{}
+	if filter != nil {
+		r.filter = *filter
+	}
+
+	for _, e := range experiment.Executions {
+		if e == nil {
+			continue
+		}
+
+		logs, err := h.client.Execution(e.ID).getLogs(ctx, r.filter.Since)
+		if err != nil {
+			_ = r.Close()
+			return nil, err
+		}
+		r.closers = append(r.closers, logs)
+
+		task := e.Spec.Name
+		if task == "" {
+			task = e.Task
+		}
+		r.sources = append(r.sources, &logSource{scanner: newLogScanner(logs), task: task})
+	}
+	return r, nil
+}
@@ -0,0 +1,265 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beaker/client/api"
+)
+
+func TestParseLogLine(t *testing.T) {
+	record, ok := parseLogLine("2021-03-01T12:00:00.123456789Z hello world\n")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2021, 3, 1, 12, 0, 0, 123456789, time.UTC), record.Time)
+	assert.Equal(t, "hello world", record.Message)
+
+	record, ok = parseLogLine("2021-03-01T12:00:00Z")
+	assert.True(t, ok)
+	assert.Equal(t, "", record.Message)
+
+	record, ok = parseLogLine("  continued\r\n")
+	assert.False(t, ok)
+	assert.True(t, record.Time.IsZero())
+	assert.Equal(t, "  continued", record.Message)
+}
+
+func TestFollowLogs(t *testing.T) {
+	base := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	line := func(offset int, message string) string {
+		return fmt.Sprintf("%s %s\n", base.Add(time.Duration(offset)*time.Second).Format(time.RFC3339Nano), message)
+	}
+
+	// Each poll serves all lines written so far, ignoring "since", to verify
+	// that the stream removes duplicates itself. Two lines share a timestamp.
+	polls := [][]string{
+		{line(0, "a"), line(1, "b")},
+		{line(0, "a"), line(1, "b"), line(1, "c")},
+		{line(0, "a"), line(1, "b"), line(1, "c"), line(2, "d")},
+	}
+
+	var mu sync.Mutex
+	poll := 0
+	var sinces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/v3/executions/ex1":
+			var execution api.Execution
+			if poll >= len(polls)-1 {
+				execution.State.Finalized = &base
+			}
+			_ = json.NewEncoder(w).Encode(execution)
+
+		case "/api/v3/executions/ex1/logs":
+			sinces = append(sinces, r.URL.Query().Get("since"))
+			_, _ = w.Write([]byte(strings.Join(polls[poll], "")))
+			if poll < len(polls)-1 {
+				poll++
+			}
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	require.NoError(t, err)
+
+	stream := client.Execution("ex1").FollowLogs(context.Background(), &FollowOptions{PollInterval: time.Millisecond})
+	var messages []string
+	for stream.Next() {
+		messages = append(messages, stream.Value().Message)
+	}
+	require.NoError(t, stream.Err())
+	assert.Equal(t, []string{"a", "b", "c", "d"}, messages)
+	assert.Equal(t, []string{
+		"",
+		base.Add(time.Second).Format(time.RFC3339Nano),
+		base.Add(time.Second).Format(time.RFC3339Nano),
+	}, sinces)
+}
+
+func TestLogReader(t *testing.T) {
+	base := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	for i, message := range []string{"start", "epoch 1 loss=0.5", "epoch 2 loss=0.3", "epoch 3 loss=0.2", "done"} {
+		fmt.Fprintf(&b, "%s %s\n", base.Add(time.Duration(i)*time.Second).Format(time.RFC3339Nano), message)
+	}
+	logs := b.String()
+
+	cases := map[string]struct {
+		filter   *LogFilter
+		expected []string
+	}{
+		"All": {
+			expected: []string{"start", "epoch 1 loss=0.5", "epoch 2 loss=0.3", "epoch 3 loss=0.2", "done"},
+		},
+		"Window": {
+			filter:   &LogFilter{Since: base.Add(time.Second), Until: base.Add(3 * time.Second)},
+			expected: []string{"epoch 1 loss=0.5", "epoch 2 loss=0.3"},
+		},
+		"Contains": {
+			filter:   &LogFilter{Contains: "epoch"},
+			expected: []string{"epoch 1 loss=0.5", "epoch 2 loss=0.3", "epoch 3 loss=0.2"},
+		},
+		"Pattern": {
+			filter:   &LogFilter{Pattern: regexp.MustCompile(`loss=0\.[23]$`)},
+			expected: []string{"epoch 2 loss=0.3", "epoch 3 loss=0.2"},
+		},
+		"Head": {
+			filter:   &LogFilter{Contains: "epoch", Head: 2},
+			expected: []string{"epoch 1 loss=0.5", "epoch 2 loss=0.3"},
+		},
+		"Tail": {
+			filter:   &LogFilter{Tail: 2},
+			expected: []string{"epoch 3 loss=0.2", "done"},
+		},
+		"HeadAndTail": {
+			filter:   &LogFilter{Head: 3, Tail: 1},
+			expected: []string{"epoch 2 loss=0.3"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewLogReader(strings.NewReader(logs), c.filter)
+			var messages []string
+			for r.Next() {
+				messages = append(messages, r.Value().Message)
+			}
+			require.NoError(t, r.Err())
+			assert.Equal(t, c.expected, messages)
+		})
+	}
+}
+
+func TestLogReaderMerge(t *testing.T) {
+	base := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	line := func(offset int, message string) string {
+		return fmt.Sprintf("%s %s\n", base.Add(time.Duration(offset)*time.Second).Format(time.RFC3339Nano), message)
+	}
+
+	r := &LogReader{sources: []*logSource{
+		{scanner: newLogScanner(strings.NewReader(line(0, "a0") + line(2, "a2") + "a2 continued\n")), task: "a"},
+		{scanner: newLogScanner(strings.NewReader(line(1, "b1") + line(3, "b3"))), task: "b"},
+	}}
+
+	var records []string
+	for r.Next() {
+		records = append(records, r.Value().Task+":"+r.Value().Message)
+	}
+	require.NoError(t, r.Err())
+	assert.Equal(t, []string{"a:a0", "b:b1", "a:a2", "a:a2 continued", "b:b3"}, records)
+}
+
+// logServer serves an execution whose logs are written by handler on each
+// poll. The execution is finalized once handler returns true.
+func logServer(t *testing.T, handler func(w http.ResponseWriter, poll int) bool) *Client {
+	var mu sync.Mutex
+	poll := 0
+	finalized := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/v3/executions/ex1":
+			var execution api.Execution
+			if finalized {
+				now := time.Now()
+				execution.State.Finalized = &now
+			}
+			_ = json.NewEncoder(w).Encode(execution)
+
+		case "/api/v3/executions/ex1/logs":
+			finalized = handler(w, poll)
+			poll++
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, "")
+	require.NoError(t, err)
+	return client
+}
+
+func TestFollowLogsResume(t *testing.T) {
+	base := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	line := func(offset int, message string) string {
+		return fmt.Sprintf("%s %s\n", base.Add(time.Duration(offset)*time.Second).Format(time.RFC3339Nano), message)
+	}
+
+	cases := map[string]struct {
+		polls    []string
+		expected []string
+	}{
+		// A line which continues the last line of the previous poll takes
+		// that line's time, rather than being dropped as too old.
+		"Continuation": {
+			polls:    []string{line(1, "a"), "  a continued\n" + line(2, "b")},
+			expected: []string{"a", "  a continued", "b"},
+		},
+		// Lines skipped as duplicates of the old since mustn't affect lines
+		// at the new since.
+		"SinceAdvances": {
+			polls:    []string{line(1, "a") + line(1, "b"), line(1, "a") + line(2, "c") + line(2, "d")},
+			expected: []string{"a", "b", "c", "d"},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := logServer(t, func(w http.ResponseWriter, poll int) bool {
+				if poll < len(c.polls) {
+					_, _ = w.Write([]byte(c.polls[poll]))
+				}
+				return poll >= len(c.polls)-1
+			})
+
+			stream := client.Execution("ex1").FollowLogs(context.Background(), &FollowOptions{PollInterval: time.Millisecond})
+			var messages []string
+			for stream.Next() {
+				messages = append(messages, stream.Value().Message)
+			}
+			require.NoError(t, stream.Err())
+			assert.Equal(t, c.expected, messages)
+		})
+	}
+}
+
+func TestFollowLogsReadFailure(t *testing.T) {
+	base := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	polls := 0
+	client := logServer(t, func(w http.ResponseWriter, poll int) bool {
+		polls++
+		// Promise more content than is sent, so reading the body fails.
+		w.Header().Set("Content-Length", "1000")
+		_, _ = fmt.Fprintf(w, "%s line %d\n", base.Add(time.Duration(poll)*time.Second).Format(time.RFC3339Nano), poll)
+		return false
+	})
+
+	stream := client.Execution("ex1").FollowLogs(context.Background(), &FollowOptions{PollInterval: time.Millisecond})
+	var messages []string
+	for stream.Next() {
+		messages = append(messages, stream.Value().Message)
+	}
+	assert.Error(t, stream.Err())
+	assert.Equal(t, maxLogReadFailures, polls)
+	assert.Equal(t, []string{"line 0", "line 1", "line 2", "line 3", "line 4"}, messages)
+}
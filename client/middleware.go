@@ -0,0 +1,68 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Middleware wraps the transport used to send each attempt of a request. It
+// may inspect or modify outgoing requests and observe each response, error,
+// and latency. Use RequestAttempt to tell retries apart.
+//
+// As with any http.RoundTripper, middleware which modifies a request should
+// modify a clone rather than the original.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts an ordinary function to an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements the http.RoundTripper interface.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware adds middleware to the client. Middleware is applied in the
+// order given: the first middleware sees requests first and responses last.
+//
+// Middleware wraps the client's transport after all other options are
+// applied, so it's unaffected by option order and sees requests before they
+// are recorded by WithCassette.
+func WithMiddleware(middleware ...Middleware) Option {
+	return optionFunc(func(c *Client) error {
+		for _, m := range middleware {
+			if m == nil {
+				return errors.New("middleware must not be nil")
+			}
+		}
+		c.middleware = append(c.middleware, middleware...)
+		return nil
+	})
+}
+
+// applyMiddleware wraps the client's transport in its middleware.
+func (c *Client) applyMiddleware() {
+	if len(c.middleware) == 0 {
+		return
+	}
+
+	transport := c.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		transport = c.middleware[i](transport)
+	}
+	c.httpClient.Transport = transport
+}
+
+// attemptKey is a context key for a request's attempt counter.
+type attemptKey struct{}
+
+// RequestAttempt returns which attempt a request is, starting from 1.
+// Requests which are never retried are always the first attempt.
+func RequestAttempt(req *http.Request) int {
+	if n, ok := req.Context().Value(attemptKey{}).(*int); ok && *n > 0 {
+		return *n
+	}
+	return 1
+}
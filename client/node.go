@@ -27,6 +27,8 @@ func (h *NodeHandle) Ref() string {
 
 // Get information about a node.
 func (h *NodeHandle) Get(ctx context.Context) (*api.Node, error) {
+	ctx = withOperation(ctx, "NodeHandle.Get")
+
 	path := path.Join("/api/v3/nodes", url.PathEscape(h.id))
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -44,6 +46,8 @@ func (h *NodeHandle) Get(ctx context.Context) (*api.Node, error) {
 
 // ListExecutions retrieves all executions that are assigned to the node.
 func (h *NodeHandle) ListExecutions(ctx context.Context) (*api.Executions, error) {
+	ctx = withOperation(ctx, "NodeHandle.ListExecutions")
+
 	path := path.Join("/api/v3/nodes", url.PathEscape(h.id), "executions")
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -60,6 +64,8 @@ func (h *NodeHandle) ListExecutions(ctx context.Context) (*api.Executions, error
 
 // AssignExecutions lists all executions on a node and assigns a new one if it has none.
 func (h *NodeHandle) AssignExecutions(ctx context.Context, resources *api.NodeResources) (*api.Executions, error) {
+	ctx = withOperation(ctx, "NodeHandle.AssignExecutions")
+
 	path := path.Join("/api/v3/nodes", url.PathEscape(h.id), "executions")
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPost, path, nil, resources)
 	if err != nil {
@@ -76,6 +82,8 @@ func (h *NodeHandle) AssignExecutions(ctx context.Context, resources *api.NodeRe
 
 // Delete removes the node (marks it terminated)
 func (h *NodeHandle) Delete(ctx context.Context) error {
+	ctx = withOperation(ctx, "NodeHandle.Delete")
+
 	path := path.Join("/api/v3/nodes", url.PathEscape(h.id))
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodDelete, path, nil, nil)
 	if err != nil {
@@ -87,6 +95,8 @@ func (h *NodeHandle) Delete(ctx context.Context) error {
 
 // Patch updates the fields of a node.
 func (h *NodeHandle) Patch(ctx context.Context, patch *api.NodePatchSpec) error {
+	ctx = withOperation(ctx, "NodeHandle.Patch")
+
 	path := path.Join("/api/v3/nodes", url.PathEscape(h.id))
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, patch)
 	if err != nil {
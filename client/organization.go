@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 
 	"github.com/beaker/client/api"
 )
@@ -13,13 +14,26 @@ import (
 func (c *Client) ListOrganizations(
 	ctx context.Context,
 	cursor string,
+) ([]api.Organization, string, error) {
+	ctx = withOperation(ctx, "Client.ListOrganizations")
+	return c.listOrganizations(ctx, cursor, 0)
+}
+
+func (c *Client) listOrganizations(
+	ctx context.Context,
+	cursor string,
+	limit int,
 ) ([]api.Organization, string, error) {
 	query := url.Values{}
 	query.Add("cursor", cursor)
+	if limit > 0 {
+		query.Add("limit", strconv.Itoa(limit))
+	}
 	resp, err := c.sendRetryableRequest(ctx, http.MethodGet, "/api/v3/admin/orgs", query, nil)
 	if err != nil {
 		return nil, "", err
 	}
+	defer safeClose(resp.Body)
 
 	var result api.OrganizationPage
 	if err := parseResponse(resp, &result); err != nil {
@@ -31,6 +45,8 @@ func (c *Client) ListOrganizations(
 // ListMyOrgs lists all orgs in which the caller is a member. The caller's
 // account is inferred from the client's auth token.
 func (c *Client) ListMyOrgs(ctx context.Context) ([]api.Organization, error) {
+	ctx = withOperation(ctx, "Client.ListMyOrgs")
+
 	resp, err := c.sendRetryableRequest(ctx, http.MethodGet, path.Join("/api/v3/user/orgs"), nil, nil)
 	if err != nil {
 		return nil, err
@@ -51,6 +67,8 @@ func (c *Client) CreateOrganization(
 	ctx context.Context,
 	spec api.OrganizationSpec,
 ) (*OrgHandle, error) {
+	ctx = withOperation(ctx, "Client.CreateOrganization")
+
 	resp, err := c.sendRetryableRequest(ctx, http.MethodPost, "/api/v3/admin/orgs", nil, spec)
 	if err != nil {
 		return nil, err
@@ -82,6 +100,8 @@ func (h *OrgHandle) Ref() string {
 
 // Get retrieves an organization's details.
 func (h *OrgHandle) Get(ctx context.Context) (*api.Organization, error) {
+	ctx = withOperation(ctx, "OrgHandle.Get")
+
 	path := path.Join("/api/v3/orgs", url.PathEscape(h.ref))
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -97,15 +117,27 @@ func (h *OrgHandle) Get(ctx context.Context) (*api.Organization, error) {
 	return &org, nil
 }
 
-// ListMembers retrieves an organization's details.
-// TODO: Make this return an iterator.
+// ListMembers retrieves an organization's members. See IterateMembers to
+// enumerate all members without handling cursors.
 func (h *OrgHandle) ListMembers(
 	ctx context.Context,
 	cursor string,
 ) (users []api.UserDetail, next string, err error) {
+	ctx = withOperation(ctx, "OrgHandle.ListMembers")
+	return h.listMembers(ctx, cursor, 0)
+}
+
+func (h *OrgHandle) listMembers(
+	ctx context.Context,
+	cursor string,
+	limit int,
+) ([]api.UserDetail, string, error) {
 	path := path.Join("/api/v3/orgs", url.PathEscape(h.ref), "members")
 	query := url.Values{}
 	query.Add("cursor", cursor)
+	if limit > 0 {
+		query.Add("limit", strconv.Itoa(limit))
+	}
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, query, nil)
 	if err != nil {
 		return nil, "", err
@@ -122,6 +154,8 @@ func (h *OrgHandle) ListMembers(
 
 // GetMember returns details about a specific membership, if it exists.
 func (h *OrgHandle) GetMember(ctx context.Context, account string) (*api.OrgMembership, error) {
+	ctx = withOperation(ctx, "OrgHandle.GetMember")
+
 	path := path.Join("/api/v3/orgs", url.PathEscape(h.ref), "members", account)
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -140,6 +174,8 @@ func (h *OrgHandle) GetMember(ctx context.Context, account string) (*api.OrgMemb
 // SetMember adds or updates the given account as a member of the org.
 // Role must be "admin" or "member".
 func (h *OrgHandle) SetMember(ctx context.Context, account string, role string) error {
+	ctx = withOperation(ctx, "OrgHandle.SetMember")
+
 	path := path.Join("/api/v3/orgs", url.PathEscape(h.ref), "members", account)
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPut, path, nil, nil)
 	if err != nil {
@@ -151,6 +187,8 @@ func (h *OrgHandle) SetMember(ctx context.Context, account string, role string)
 
 // RemoveMember removes the given account from the org.
 func (h *OrgHandle) RemoveMember(ctx context.Context, account string) error {
+	ctx = withOperation(ctx, "OrgHandle.RemoveMember")
+
 	path := path.Join("/api/v3/orgs", url.PathEscape(h.ref), "members", account)
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodDelete, path, nil, nil)
 	if err != nil {
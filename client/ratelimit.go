@@ -0,0 +1,143 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimit bounds the throughput of a client's requests.
+type RateLimit struct {
+	// (optional) RequestsPerSecond is the sustained rate at which requests may
+	// be sent. Each attempt of a retried request counts separately. Zero
+	// disables rate limiting.
+	RequestsPerSecond float64
+
+	// (optional) Burst is the number of requests which may be sent at once
+	// before the rate applies. Defaults to 1.
+	Burst int
+
+	// (optional) MaxInFlight is the maximum number of concurrent requests.
+	// A request remains in flight until its response body is closed. Zero
+	// allows any number of concurrent requests.
+	MaxInFlight int
+}
+
+// WithRateLimit limits the rate and concurrency of requests sent by the client.
+// Limits are shared by all handles created from the client. Requests wait for
+// capacity until their context is done.
+func WithRateLimit(limit RateLimit) Option {
+	return optionFunc(func(c *Client) error {
+		if limit.RequestsPerSecond < 0 {
+			return errors.New("rate limit must not be negative")
+		}
+		if limit.Burst < 0 {
+			return errors.New("rate limit burst must not be negative")
+		}
+		if limit.MaxInFlight < 0 {
+			return errors.New("max in-flight requests must not be negative")
+		}
+		c.rateLimit = &limit
+		return nil
+	})
+}
+
+// applyRateLimit wraps the client's transport to enforce its rate limit.
+func (c *Client) applyRateLimit() {
+	if c.rateLimit == nil {
+		return
+	}
+
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &limitTransport{next: next}
+	if rps := c.rateLimit.RequestsPerSecond; rps > 0 {
+		burst := c.rateLimit.Burst
+		if burst == 0 {
+			burst = 1
+		}
+		t.limiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+	if n := c.rateLimit.MaxInFlight; n > 0 {
+		t.inFlight = make(chan struct{}, n)
+	}
+	c.httpClient.Transport = t
+}
+
+// limitTransport is a transport which waits for capacity before each request.
+type limitTransport struct {
+	next     http.RoundTripper
+	limiter  *rate.Limiter // Nil if unlimited.
+	inFlight chan struct{} // Nil if unlimited.
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.inFlight != nil {
+		select {
+		case t.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			closeRequest(req)
+			return nil, ctx.Err()
+		}
+	}
+
+	// Wait for the rate limit only once admitted so that queued requests
+	// don't hoard tokens.
+	if t.limiter != nil {
+		r := t.limiter.Reserve()
+		if delay := r.Delay(); delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				r.Cancel()
+				t.release()
+				closeRequest(req)
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || t.inFlight == nil {
+		t.release()
+		return resp, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: t.release}
+	return resp, nil
+}
+
+func (t *limitTransport) release() {
+	if t.inFlight != nil {
+		<-t.inFlight
+	}
+}
+
+// closeRequest closes a request's body, as transports must even on failure.
+func closeRequest(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
+
+// releaseOnClose frees a request's in-flight slot once its body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/beaker/client/api"
+)
+
+// NodeUsageOptions selects the data in a node usage report.
+type NodeUsageOptions struct {
+	// (required) Start and End bound the period covered by the report.
+	Start time.Time
+	End   time.Time
+
+	// (optional) Interval is the length of each period within the report.
+	// Defaults to api.IntervalDay.
+	Interval api.Interval
+
+	// (optional) Metric is the quantity to report. Defaults to api.NodeMetricHours.
+	Metric api.NodeMetric
+
+	// (optional) GroupBy splits the report into a series for each combination
+	// of values of the given dimensions.
+	GroupBy []api.NodeGroupBy
+}
+
+// TaskUsageOptions selects the data in a task usage report.
+type TaskUsageOptions struct {
+	// (required) Start and End bound the period covered by the report.
+	Start time.Time
+	End   time.Time
+
+	// (optional) Interval is the length of each period within the report.
+	// Defaults to api.IntervalDay.
+	Interval api.Interval
+
+	// (optional) Metric is the quantity to report. Defaults to api.TaskMetricHours.
+	Metric api.TaskMetric
+
+	// (optional) GroupBy splits the report into a series for each combination
+	// of values of the given dimensions.
+	GroupBy []api.TaskGroupBy
+}
+
+// NodeUsageReport reports the usage of all nodes visible to the caller.
+//
+// Experimental: the report endpoint and its query parameters haven't been
+// verified against the service and may change.
+func (c *Client) NodeUsageReport(ctx context.Context, opts *NodeUsageOptions) (*api.NodeUsageReport, error) {
+	ctx = withOperation(ctx, "Client.NodeUsageReport")
+	return c.nodeUsageReport(ctx, nil, opts)
+}
+
+// TaskUsageReport reports the usage of all tasks visible to the caller.
+//
+// Experimental: the report endpoint and its query parameters haven't been
+// verified against the service and may change.
+func (c *Client) TaskUsageReport(ctx context.Context, opts *TaskUsageOptions) (*api.TaskUsageReport, error) {
+	ctx = withOperation(ctx, "Client.TaskUsageReport")
+	return c.taskUsageReport(ctx, nil, opts)
+}
+
+// NodeUsageReport reports the usage of the cluster's nodes.
+//
+// Experimental: the report endpoint and its query parameters haven't been
+// verified against the service and may change.
+func (h *ClusterHandle) NodeUsageReport(ctx context.Context, opts *NodeUsageOptions) (*api.NodeUsageReport, error) {
+	ctx = withOperation(ctx, "ClusterHandle.NodeUsageReport")
+
+	if err := validateClusterRef(h.ref); err != nil {
+		return nil, err
+	}
+	return h.client.nodeUsageReport(ctx, url.Values{"cluster": {h.ref}}, opts)
+}
+
+// TaskUsageReport reports the usage of tasks run on the cluster.
+//
+// Experimental: the report endpoint and its query parameters haven't been
+// verified against the service and may change.
+func (h *ClusterHandle) TaskUsageReport(ctx context.Context, opts *TaskUsageOptions) (*api.TaskUsageReport, error) {
+	ctx = withOperation(ctx, "ClusterHandle.TaskUsageReport")
+
+	if err := validateClusterRef(h.ref); err != nil {
+		return nil, err
+	}
+	return h.client.taskUsageReport(ctx, url.Values{"cluster": {h.ref}}, opts)
+}
+
+// TaskUsageReport reports the usage of tasks in the workspace.
+//
+// Experimental: the report endpoint and its query parameters haven't been
+// verified against the service and may change.
+func (h *WorkspaceHandle) TaskUsageReport(ctx context.Context, opts *TaskUsageOptions) (*api.TaskUsageReport, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.TaskUsageReport")
+	return h.client.taskUsageReport(ctx, url.Values{"workspace": {h.ref}}, opts)
+}
+
+// NodeUsageReport reports the usage of nodes in the organization's clusters.
+//
+// Experimental: the report endpoint and its query parameters haven't been
+// verified against the service and may change.
+func (h *OrgHandle) NodeUsageReport(ctx context.Context, opts *NodeUsageOptions) (*api.NodeUsageReport, error) {
+	ctx = withOperation(ctx, "OrgHandle.NodeUsageReport")
+	return h.client.nodeUsageReport(ctx, url.Values{"org": {h.ref}}, opts)
+}
+
+// TaskUsageReport reports the usage of tasks in the organization's workspaces.
+//
+// Experimental: the report endpoint and its query parameters haven't been
+// verified against the service and may change.
+func (h *OrgHandle) TaskUsageReport(ctx context.Context, opts *TaskUsageOptions) (*api.TaskUsageReport, error) {
+	ctx = withOperation(ctx, "OrgHandle.TaskUsageReport")
+	return h.client.taskUsageReport(ctx, url.Values{"org": {h.ref}}, opts)
+}
+
+func (c *Client) nodeUsageReport(
+	ctx context.Context,
+	scope url.Values,
+	opts *NodeUsageOptions,
+) (*api.NodeUsageReport, error) {
+	if opts == nil {
+		return nil, errors.New("report options are required")
+	}
+
+	metric := opts.Metric
+	if metric == "" {
+		metric = api.NodeMetricHours
+	}
+	groupBy := make([]string, len(opts.GroupBy))
+	for i, g := range opts.GroupBy {
+		groupBy[i] = string(g)
+	}
+
+	query, err := reportQuery(scope, opts.Start, opts.End, opts.Interval, string(metric), groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendRetryableRequest(ctx, http.MethodGet, "/api/v3/reports/nodes", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer safeClose(resp.Body)
+
+	var report api.NodeUsageReport
+	if err := parseResponse(resp, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (c *Client) taskUsageReport(
+	ctx context.Context,
+	scope url.Values,
+	opts *TaskUsageOptions,
+) (*api.TaskUsageReport, error) {
+	if opts == nil {
+		return nil, errors.New("report options are required")
+	}
+
+	metric := opts.Metric
+	if metric == "" {
+		metric = api.TaskMetricHours
+	}
+	groupBy := make([]string, len(opts.GroupBy))
+	for i, g := range opts.GroupBy {
+		groupBy[i] = string(g)
+	}
+
+	query, err := reportQuery(scope, opts.Start, opts.End, opts.Interval, string(metric), groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendRetryableRequest(ctx, http.MethodGet, "/api/v3/reports/tasks", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer safeClose(resp.Body)
+
+	var report api.TaskUsageReport
+	if err := parseResponse(resp, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// reportQuery encodes the parameters of a usage report.
+func reportQuery(
+	scope url.Values,
+	start, end time.Time,
+	interval api.Interval,
+	metric string,
+	groupBy []string,
+) (url.Values, error) {
+	if start.IsZero() || end.IsZero() {
+		return nil, errors.New("report start and end are required")
+	}
+	if !end.After(start) {
+		return nil, errors.New("report end must be after its start")
+	}
+
+	switch interval {
+	case "":
+		interval = api.IntervalDay
+	case api.IntervalHour, api.IntervalDay, api.IntervalWeek,
+		api.IntervalMonth, api.IntervalQuarter, api.IntervalYear:
+	default:
+		return nil, errors.New("invalid report interval: " + string(interval))
+	}
+
+	query := url.Values{}
+	for key, values := range scope {
+		query[key] = values
+	}
+	query.Set("start", start.UTC().Format(time.RFC3339))
+	query.Set("end", end.UTC().Format(time.RFC3339))
+	query.Set("interval", string(interval))
+	query.Set("metric", metric)
+	for _, g := range groupBy {
+		query.Add("groupBy", g)
+	}
+	return query, nil
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beaker/client/api"
+)
+
+func TestUsageReports(t *testing.T) {
+	var path string
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.Query()
+		_, _ = w.Write([]byte(`{
+			"totals": {"start": "2021-01-01T00:00:00Z", "end": "2021-02-01T00:00:00Z", "value": 48},
+			"series": [{"cluster": "ai2/gpu", "gpuType": "A100", "totals": {"value": 48}, "intervals": []}]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	nodes, err := client.Cluster("ai2/gpu").NodeUsageReport(ctx, &NodeUsageOptions{
+		Start:   start,
+		End:     end,
+		Metric:  api.NodeMetricGPUHours,
+		GroupBy: []api.NodeGroupBy{api.NodeGroupByCluster, api.NodeGroupByGPUType},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v3/reports/nodes", path)
+	assert.Equal(t, url.Values{
+		"cluster":  {"ai2/gpu"},
+		"start":    {"2021-01-01T00:00:00Z"},
+		"end":      {"2021-02-01T00:00:00Z"},
+		"interval": {"day"},
+		"metric":   {"gpuHours"},
+		"groupBy":  {"cluster", "gpuType"},
+	}, query)
+	assert.Equal(t, 48.0, nodes.Totals.Value)
+	require.Len(t, nodes.Series, 1)
+	assert.Equal(t, "A100", *nodes.Series[0].GPUType)
+
+	tasks, err := client.Workspace("ai2/ws").TaskUsageReport(ctx, &TaskUsageOptions{
+		Start:    start,
+		End:      end,
+		Interval: api.IntervalMonth,
+		GroupBy:  []api.TaskGroupBy{api.TaskGroupByAuthor},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v3/reports/tasks", path)
+	assert.Equal(t, url.Values{
+		"workspace": {"ai2/ws"},
+		"start":     {"2021-01-01T00:00:00Z"},
+		"end":       {"2021-02-01T00:00:00Z"},
+		"interval":  {"month"},
+		"metric":    {"hours"},
+		"groupBy":   {"author"},
+	}, query)
+	assert.Equal(t, 48.0, tasks.Totals.Value)
+
+	cases := map[string]TaskUsageOptions{
+		"MissingStart":    {End: end},
+		"EndBeforeStart":  {Start: end, End: start},
+		"InvalidInterval": {Start: start, End: end, Interval: "fortnight"},
+	}
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.TaskUsageReport(ctx, &opts)
+			assert.Error(t, err)
+		})
+	}
+}
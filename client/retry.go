@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	retryable "github.com/hashicorp/go-retryablehttp"
+)
+
+// RetryPolicy controls how a client retries failed requests.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried after its
+	// first attempt. Zero disables retries.
+	MaxRetries int
+
+	// WaitMin and WaitMax bound the time to wait between attempts. Waits grow
+	// exponentially with each attempt, with full jitter.
+	WaitMin time.Duration
+	WaitMax time.Duration
+
+	// (optional) StatusCodes lists the response codes which should be retried.
+	// If empty, requests are retried on 429 and all 5xx codes except 501.
+	// Connection errors are always retried.
+	StatusCodes []int
+
+	// (optional) Methods lists the HTTP methods which may be retried. If empty,
+	// requests of any method may be retried. Omit http.MethodPost to avoid
+	// retrying non-idempotent calls such as CreateExperiment.
+	Methods []string
+
+	// RespectRetryAfter waits for the duration requested by a server's
+	// Retry-After header on 429 and 503 responses, up to WaitMax.
+	RespectRetryAfter bool
+}
+
+// DefaultRetryPolicy returns the policy used by clients unless otherwise configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 9,
+		WaitMin:    100 * time.Millisecond,
+		WaitMax:    30 * time.Second,
+	}
+}
+
+// WithRetryPolicy sets the policy for retrying failed requests. If not
+// specified, DefaultRetryPolicy is used.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return optionFunc(func(c *Client) error {
+		if policy.MaxRetries < 0 {
+			return errors.New("retry policy must not have negative retries")
+		}
+		if policy.WaitMin < 0 || policy.WaitMax < policy.WaitMin {
+			return errors.New("retry policy must have 0 <= WaitMin <= WaitMax")
+		}
+		c.retryPolicy = policy
+		return nil
+	})
+}
+
+// checkRetry returns a retryable.CheckRetry which applies the policy to
+// requests of the given method.
+func (p RetryPolicy) checkRetry(method string) retryable.CheckRetry {
+	methodAllowed := len(p.Methods) == 0
+	for _, m := range p.Methods {
+		if m == method {
+			methodAllowed = true
+			break
+		}
+	}
+
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if !methodAllowed {
+			return false, nil
+		}
+		if errors.Is(err, ErrNotRecorded) {
+			// Replayed responses never change, so retrying is pointless.
+			return false, nil
+		}
+		if err != nil || len(p.StatusCodes) == 0 {
+			return retryable.DefaultRetryPolicy(ctx, resp, err)
+		}
+		for _, code := range p.StatusCodes {
+			if resp.StatusCode == code {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// backoff returns the time to wait before the next attempt.
+func (p RetryPolicy) backoff(
+	minDuration, maxDuration time.Duration,
+	attempt int,
+	resp *http.Response,
+) time.Duration {
+	if p.RespectRetryAfter && resp != nil &&
+		(resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if wait > maxDuration {
+				return maxDuration
+			}
+			if wait < minDuration {
+				return minDuration
+			}
+			return wait
+		}
+	}
+	return exponentialJitterBackoff(minDuration, maxDuration, attempt, resp)
+}
+
+// parseRetryAfter parses a Retry-After header, which may be either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(t); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	cases := map[string]struct {
+		policy   RetryPolicy
+		method   string
+		status   int
+		attempts int32
+	}{
+		"DefaultStatusCodes": {
+			policy:   RetryPolicy{MaxRetries: 2, WaitMin: time.Millisecond, WaitMax: time.Millisecond},
+			method:   http.MethodPost,
+			status:   http.StatusBadGateway,
+			attempts: 3,
+		},
+		"Disabled": {
+			policy:   RetryPolicy{},
+			method:   http.MethodGet,
+			status:   http.StatusBadGateway,
+			attempts: 1,
+		},
+		"NotRetryable": {
+			policy:   RetryPolicy{MaxRetries: 2, WaitMin: time.Millisecond, WaitMax: time.Millisecond},
+			method:   http.MethodGet,
+			status:   http.StatusNotFound,
+			attempts: 1,
+		},
+		"StatusCodes": {
+			policy: RetryPolicy{
+				MaxRetries:  2,
+				WaitMin:     time.Millisecond,
+				WaitMax:     time.Millisecond,
+				StatusCodes: []int{http.StatusConflict},
+			},
+			method:   http.MethodGet,
+			status:   http.StatusConflict,
+			attempts: 3,
+		},
+		"MethodExcluded": {
+			policy: RetryPolicy{
+				MaxRetries: 2,
+				WaitMin:    time.Millisecond,
+				WaitMax:    time.Millisecond,
+				Methods:    []string{http.MethodGet},
+			},
+			method:   http.MethodPost,
+			status:   http.StatusBadGateway,
+			attempts: 1,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(c.status)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(server.URL, "", WithRetryPolicy(c.policy))
+			require.NoError(t, err)
+
+			resp, err := client.sendRetryableRequest(context.Background(), c.method, "/", nil, nil)
+			require.NoError(t, err)
+			safeClose(resp.Body)
+			assert.Equal(t, c.status, resp.StatusCode)
+			assert.Equal(t, c.attempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestRetryPolicyRetryAfter(t *testing.T) {
+	policy := RetryPolicy{WaitMin: time.Millisecond, WaitMax: time.Minute, RespectRetryAfter: true}
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+
+	resp.Header.Set("Retry-After", "5")
+	assert.Equal(t, 5*time.Second, policy.backoff(policy.WaitMin, policy.WaitMax, 0, resp))
+
+	resp.Header.Set("Retry-After", "3600")
+	assert.Equal(t, time.Minute, policy.backoff(policy.WaitMin, policy.WaitMax, 0, resp))
+
+	policy.RespectRetryAfter = false
+	resp.Header.Set("Retry-After", "5")
+	assert.True(t, policy.backoff(policy.WaitMin, policy.WaitMax, 0, resp) <= 2*time.Millisecond)
+}
+
+func TestWithRetryPolicyInvalid(t *testing.T) {
+	_, err := NewClient("localhost", "", WithRetryPolicy(RetryPolicy{MaxRetries: -1}))
+	assert.Error(t, err)
+
+	_, err = NewClient("localhost", "", WithRetryPolicy(RetryPolicy{WaitMin: time.Second}))
+	assert.Error(t, err)
+}
@@ -12,6 +12,8 @@ import (
 
 // CreateSession creates an interactive Beaker session.
 func (c *Client) CreateSession(ctx context.Context, spec api.SessionSpec) (*api.Session, error) {
+	ctx = withOperation(ctx, "Client.CreateSession")
+
 	path := path.Join("/api/v3/sessions")
 	resp, err := c.sendRetryableRequest(ctx, http.MethodPost, path, nil, spec)
 	if err != nil {
@@ -38,6 +40,8 @@ func (c *Client) ListSessions(
 	ctx context.Context,
 	opts *ListSessionOpts,
 ) ([]api.Session, error) {
+	ctx = withOperation(ctx, "Client.ListSessions")
+
 	if opts == nil {
 		opts = &ListSessionOpts{}
 	}
@@ -85,6 +89,8 @@ func (h *SessionHandle) Ref() string {
 
 // Get retrieves an session's details.
 func (h *SessionHandle) Get(ctx context.Context) (*api.Session, error) {
+	ctx = withOperation(ctx, "SessionHandle.Get")
+
 	path := path.Join("/api/v3/sessions", url.PathEscape(h.id))
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -101,6 +107,8 @@ func (h *SessionHandle) Get(ctx context.Context) (*api.Session, error) {
 
 // Patch updates a session.
 func (h *SessionHandle) Patch(ctx context.Context, patch api.SessionPatch) (*api.Session, error) {
+	ctx = withOperation(ctx, "SessionHandle.Patch")
+
 	path := path.Join("/api/v3/sessions", url.PathEscape(h.id))
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, patch)
 	if err != nil {
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/beaker/client/api"
+)
+
+// SweepOptions configures how a sweep is created.
+type SweepOptions struct {
+	// (required) Name of the group which collects the sweep's experiments.
+	// Experiments are named after the group, with a numeric suffix if the
+	// sweep is split into more than one.
+	Name string
+
+	// (optional) Description of the group.
+	Description string
+
+	// (optional) MaxTasksPerExperiment splits the sweep into multiple
+	// experiments of at most this many tasks each.
+	MaxTasksPerExperiment int
+}
+
+// SweepResult describes the objects created for a sweep.
+type SweepResult struct {
+	// Group contains every experiment in the sweep. It's nil if the group
+	// couldn't be created.
+	Group *GroupHandle
+
+	// Experiments are the created experiments, in the order of their tasks.
+	Experiments []api.Experiment
+}
+
+// CreateSweep expands a spec with a sweep's parameters, creates the resulting
+// experiments in the workspace, and adds them to a new group. See
+// api.Sweep.ExpandSpec for how tasks are expanded.
+//
+// Specs are validated before anything is created. If creation fails part way,
+// the result lists the objects which were created along with the error.
+func (h *WorkspaceHandle) CreateSweep(
+	ctx context.Context,
+	base *api.ExperimentSpecV2,
+	sweep *api.Sweep,
+	opts *SweepOptions,
+) (*SweepResult, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.CreateSweep")
+
+	if opts == nil || opts.Name == "" {
+		return nil, errors.New("sweep name is required")
+	}
+	if base == nil {
+		return nil, errors.New("base spec is required")
+	}
+	if sweep == nil {
+		return nil, errors.New("sweep is required")
+	}
+
+	specs, err := sweep.ExpandSpec(*base, opts.MaxTasksPerExperiment)
+	if err != nil {
+		return nil, err
+	}
+	for i := range specs {
+		if err := specs[i].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid spec: %w", err)
+		}
+	}
+
+	result := &SweepResult{}
+	ids := make([]string, 0, len(specs))
+	for i := range specs {
+		name := opts.Name
+		if len(specs) > 1 {
+			name = fmt.Sprintf("%s-%d", opts.Name, i)
+		}
+
+		experiment, err := h.CreateExperiment(ctx, &specs[i], &ExperimentOpts{Name: name})
+		if err != nil {
+			return result, fmt.Errorf("creating experiment %q: %w", name, err)
+		}
+		result.Experiments = append(result.Experiments, *experiment)
+		ids = append(ids, experiment.ID)
+	}
+
+	result.Group, err = h.client.CreateGroup(ctx, api.GroupSpec{
+		Workspace:   h.ref,
+		Name:        opts.Name,
+		Description: opts.Description,
+		Experiments: ids,
+	})
+	if err != nil {
+		return result, fmt.Errorf("creating group %q: %w", opts.Name, err)
+	}
+	return result, nil
+}
@@ -27,6 +27,8 @@ func (h *TaskHandle) Ref() string {
 
 // Get retrieves a task's details.
 func (h *TaskHandle) Get(ctx context.Context) (*api.Task, error) {
+	ctx = withOperation(ctx, "TaskHandle.Get")
+
 	path := path.Join("/api/v3/tasks", url.PathEscape(h.id))
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
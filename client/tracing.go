@@ -0,0 +1,287 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/beaker/client/api"
+)
+
+// instrumentationName identifies spans created by this package.
+const instrumentationName = "github.com/beaker/client"
+
+// Attributes recorded on spans in addition to standard HTTP attributes.
+const (
+	// RefAttribute is the resource a request operates on, as given by the caller.
+	RefAttribute = attribute.Key("beaker.ref")
+
+	// AttemptAttribute is the 1-based attempt number of a request.
+	AttemptAttribute = attribute.Key("beaker.attempt")
+
+	// ErrorIDAttribute is the ID of an error returned by the service.
+	ErrorIDAttribute = attribute.Key("beaker.error_id")
+)
+
+// TracingOptions configures OpenTelemetry instrumentation.
+type TracingOptions struct {
+	// TracerProvider creates the client's tracer. If nil, the global provider
+	// is used.
+	TracerProvider trace.TracerProvider
+
+	// Propagator injects trace context into request headers. If nil, the
+	// global propagator is used.
+	Propagator propagation.TextMapPropagator
+}
+
+// WithTracing instruments the client with OpenTelemetry. Each request creates
+// a span named after the public method which sent it, such as
+// "ExperimentHandle.Get", with a child span for each attempt to send the
+// request. Methods which send several requests, such as ExperimentHandle.Wait,
+// create a span for each. Trace context is propagated to the service through
+// request headers.
+//
+// Spans record the route template, such as "/api/v3/experiments/{ref}", the
+// resource ref, HTTP status code, and the ID of any error returned by the
+// service. A nil options value uses the global provider and propagator.
+func WithTracing(opts *TracingOptions) Option {
+	return optionFunc(func(c *Client) error {
+		var o TracingOptions
+		if opts != nil {
+			o = *opts
+		}
+		if o.TracerProvider == nil {
+			o.TracerProvider = otel.GetTracerProvider()
+		}
+		if o.Propagator == nil {
+			o.Propagator = otel.GetTextMapPropagator()
+		}
+		c.tracer = o.TracerProvider.Tracer(instrumentationName)
+		c.propagator = o.Propagator
+		return nil
+	})
+}
+
+// applyTracing wraps the client's transport to trace each attempt. It must be
+// the outermost transport so that middleware sees propagated headers.
+func (c *Client) applyTracing() {
+	if c.tracer == nil {
+		return
+	}
+
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = &tracingTransport{
+		next:       next,
+		tracer:     c.tracer,
+		propagator: c.propagator,
+	}
+}
+
+type operationKey struct{}
+
+// withOperation names the public method on whose behalf requests are sent with
+// the returned context. Methods called by another public method keep the name
+// of the outermost one.
+func withOperation(ctx context.Context, name string) context.Context {
+	if _, ok := ctx.Value(operationKey{}).(string); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+// startOperation starts a span for a request, including all of its attempts.
+// The span is a no-op if tracing is disabled.
+func (c *Client) startOperation(ctx context.Context, req *http.Request) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	route := routeTemplate(req.URL.Path)
+	name, ok := ctx.Value(operationKey{}).(string)
+	if !ok {
+		name = req.Method + " " + route
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(req.Method),
+		semconv.HTTPRouteKey.String(route),
+	}
+	if ref := resourceRef(req.URL.Path); ref != "" {
+		attrs = append(attrs, RefAttribute.String(ref))
+	}
+	return c.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// tracingTransport creates a span for each attempt to send a request.
+type tracingTransport struct {
+	next       http.RoundTripper
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attrs := semconv.HTTPClientAttributesFromHTTPRequest(req)
+	attrs = append(attrs, AttemptAttribute.Int(RequestAttempt(req)))
+	ctx, span := t.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+
+	// Transports must not modify the request, so send a copy.
+	req = req.Clone(ctx)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	endSpan(span, resp, err)
+	return resp, err
+}
+
+// endSpan records the outcome of a request and ends its span.
+func endSpan(span trace.Span, resp *http.Response, err error) {
+	defer span.End()
+	if !span.IsRecording() {
+		return
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		if id := peekErrorID(resp); id != "" {
+			span.SetAttributes(ErrorIDAttribute.String(id))
+		}
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+}
+
+// maxErrorPeek limits how much of an error response is read to find its ID.
+const maxErrorPeek = 64 << 10
+
+// peekErrorID returns the ID of an API error in a response without consuming
+// its body.
+func peekErrorID(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+
+	b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorPeek))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(b), resp.Body), resp.Body}
+
+	var apiErr api.Error
+	if err := json.Unmarshal(b, &apiErr); err != nil {
+		return ""
+	}
+	return apiErr.ErrorID
+}
+
+// resourceCollections are API paths whose next segment is a resource ref.
+var resourceCollections = map[string]bool{
+	"clusters":    true,
+	"datasets":    true,
+	"executions":  true,
+	"experiments": true,
+	"groups":      true,
+	"images":      true,
+	"nodes":       true,
+	"orgs":        true,
+	"sessions":    true,
+	"tasks":       true,
+	"users":       true,
+	"workspaces":  true,
+}
+
+// apiPrefix begins the path of every API request.
+const apiPrefix = "/api/v3/"
+
+// refSegments returns the number of segments following a resource collection
+// which form a resource's ref, or zero if the path doesn't refer to a resource.
+func refSegments(parts []string) int {
+	if len(parts) < 2 || !resourceCollections[parts[0]] || parts[1] == "search" {
+		return 0
+	}
+
+	// Cluster names are qualified by account as two path segments.
+	if parts[0] == "clusters" && len(parts) > 2 {
+		return 2
+	}
+	return 1
+}
+
+// resourceRef returns the ref of the resource a request path refers to, if any.
+func resourceRef(p string) string {
+	if !strings.HasPrefix(p, apiPrefix) {
+		return ""
+	}
+	parts := strings.Split(strings.TrimPrefix(p, apiPrefix), "/")
+	n := refSegments(parts)
+	if n == 0 {
+		return ""
+	}
+
+	segments := parts[1 : 1+n]
+	ref := make([]string, len(segments))
+	for i, s := range segments {
+		var err error
+		if ref[i], err = url.PathUnescape(s); err != nil {
+			ref[i] = s
+		}
+	}
+	return strings.Join(ref, "/")
+}
+
+// routeTemplate replaces the variable segments of a request path with
+// placeholders, such as "/api/v3/experiments/{ref}/spec". A resource's ref may
+// be followed by a sub-collection and an item within it, such as
+// "/api/v3/workspaces/{ref}/secrets/{id}".
+func routeTemplate(p string) string {
+	if !strings.HasPrefix(p, apiPrefix) {
+		return p
+	}
+	parts := strings.Split(strings.TrimPrefix(p, apiPrefix), "/")
+	n := refSegments(parts)
+	if n == 0 {
+		return p
+	}
+
+	template := []string{parts[0]}
+	switch {
+	case parts[0] != "clusters":
+		template = append(template, "{ref}")
+	case n == 1:
+		template = append(template, "{account}")
+	default:
+		template = append(template, "{account}", "{cluster}")
+	}
+
+	rest := parts[1+n:]
+	if len(rest) > 0 {
+		template = append(template, rest[0])
+	}
+	if len(rest) > 1 {
+		template = append(template, "{id}")
+	}
+	return apiPrefix + strings.Join(template, "/")
+}
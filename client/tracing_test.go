@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/oteltest"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+func TestTracing(t *testing.T) {
+	var requests int32
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		n := atomic.AddInt32(&requests, 1)
+		code := http.StatusServiceUnavailable
+		if n > 1 {
+			code = http.StatusNotFound
+		}
+		w.WriteHeader(code)
+		fmt.Fprintf(w, `{"code": %d, "message": "failed", "error_id": "e%d"}`, code, n)
+	}))
+	defer server.Close()
+
+	recorder := new(oteltest.SpanRecorder)
+	c, err := NewClient(server.URL, "",
+		WithTracing(&TracingOptions{
+			TracerProvider: oteltest.NewTracerProvider(oteltest.WithSpanRecorder(recorder)),
+			Propagator:     propagation.TraceContext{},
+		}),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 1, WaitMin: time.Millisecond, WaitMax: time.Millisecond}),
+	)
+	require.NoError(t, err)
+
+	_, err = c.Experiment("me/exp").Get(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, fmt.Sprint(err), "error_id e2")
+
+	spans := recorder.Completed()
+	require.Len(t, spans, 3)
+	op := spans[2]
+	assert.Equal(t, "ExperimentHandle.Get", op.Name())
+	assert.Equal(t, codes.Error, op.StatusCode())
+	attrs := op.Attributes()
+	assert.Equal(t, "/api/v3/experiments/{ref}", attrs[semconv.HTTPRouteKey].AsString())
+	assert.Equal(t, "me/exp", attrs[RefAttribute].AsString())
+	assert.Equal(t, int64(http.StatusNotFound), attrs[semconv.HTTPStatusCodeKey].AsInt64())
+	assert.Equal(t, "e2", attrs[ErrorIDAttribute].AsString())
+
+	for i, attempt := range spans[:2] {
+		assert.Equal(t, "HTTP GET", attempt.Name())
+		assert.Equal(t, op.SpanContext().SpanID(), attempt.ParentSpanID())
+		attrs := attempt.Attributes()
+		assert.Equal(t, int64(i+1), attrs[AttemptAttribute].AsInt64())
+		assert.Equal(t, fmt.Sprintf("e%d", i+1), attrs[ErrorIDAttribute].AsString())
+
+		// Each attempt is propagated as the server's parent span.
+		assert.Contains(t, traceparents[i], attempt.SpanContext().SpanID().String())
+	}
+}
+
+func TestOperationNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "01F8GQ3Y4ZKX7W9M2N5P6R0T1V"}`))
+	}))
+	defer server.Close()
+
+	recorder := new(oteltest.SpanRecorder)
+	c, err := NewClient(server.URL, "", WithTracing(&TracingOptions{
+		TracerProvider: oteltest.NewTracerProvider(oteltest.WithSpanRecorder(recorder)),
+	}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = c.Experiment("me/exp").Get(ctx)
+	require.NoError(t, err)
+	spec, err := c.Experiment("me/exp").Spec(ctx, "v2", false)
+	require.NoError(t, err)
+	spec.Close()
+	_, err = c.Dataset("me/data").Resolve(ctx)
+	require.NoError(t, err)
+
+	type operation struct{ Name, Route string }
+	var actual []operation
+	for _, span := range recorder.Completed() {
+		if route, ok := span.Attributes()[semconv.HTTPRouteKey]; ok {
+			actual = append(actual, operation{span.Name(), route.AsString()})
+		}
+	}
+
+	// Operations are named after the outermost public method, so a request
+	// sent by DatasetHandle.Get on behalf of Resolve is attributed to Resolve.
+	assert.Equal(t, []operation{
+		{"ExperimentHandle.Get", "/api/v3/experiments/{ref}"},
+		{"ExperimentHandle.Spec", "/api/v3/experiments/{ref}/spec"},
+		{"DatasetHandle.Resolve", "/api/v3/datasets/{ref}"},
+	}, actual)
+}
+
+func TestResourceRef(t *testing.T) {
+	cases := map[string]struct {
+		Path     string
+		Expected string
+	}{
+		"Escaped":  {"/api/v3/workspaces/me%2Fws/experiments", "me/ws"},
+		"Cluster":  {"/api/v3/clusters/ai2/cpu/nodes", "ai2/cpu"},
+		"Account":  {"/api/v3/clusters/ai2", "ai2"},
+		"Search":   {"/api/v3/datasets/search", ""},
+		"NoRef":    {"/api/v3/user", ""},
+		"Unknown":  {"/api/v3/auth/tokens", ""},
+		"External": {"/other/datasets/d1", ""},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, resourceRef(c.Path))
+		})
+	}
+}
+
+func TestRouteTemplate(t *testing.T) {
+	cases := map[string]struct {
+		Path     string
+		Expected string
+	}{
+		"Resource":      {"/api/v3/experiments/me%2Fexp", "/api/v3/experiments/{ref}"},
+		"SubCollection": {"/api/v3/workspaces/me%2Fws/experiments", "/api/v3/workspaces/{ref}/experiments"},
+		"SubResource":   {"/api/v3/workspaces/ws1/secrets/key", "/api/v3/workspaces/{ref}/secrets/{id}"},
+		"Nested":        {"/api/v3/executions/ex1/logs/a/b.log", "/api/v3/executions/{ref}/logs/{id}"},
+		"Cluster":       {"/api/v3/clusters/ai2/cpu/nodes", "/api/v3/clusters/{account}/{cluster}/nodes"},
+		"Account":       {"/api/v3/clusters/ai2", "/api/v3/clusters/{account}"},
+		"Collection":    {"/api/v3/datasets", "/api/v3/datasets"},
+		"Search":        {"/api/v3/datasets/search", "/api/v3/datasets/search"},
+		"NoRef":         {"/api/v3/reports/nodes", "/api/v3/reports/nodes"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, routeTemplate(c.Path))
+		})
+	}
+}
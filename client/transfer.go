@@ -0,0 +1,411 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	fhapi "github.com/beaker/fileheap/api"
+	fileheap "github.com/beaker/fileheap/client"
+
+	"github.com/beaker/client/api"
+)
+
+// TransferOptions configures how files are copied to or from a dataset.
+// All fields are optional.
+type TransferOptions struct {
+	// Concurrency is the maximum number of files to transfer at once.
+	// Defaults to 8.
+	Concurrency int
+
+	// Include selects files to transfer by path.Match patterns. If empty, all
+	// files are included. Patterns match slash-separated paths relative to the
+	// dataset root, or any of their parent directories. Patterns without a
+	// slash match a file or directory name at any depth.
+	Include []string
+
+	// Exclude skips files matching any of the given patterns. Exclusions take
+	// precedence over inclusions.
+	Exclude []string
+
+	// Verify compares the SHA256 digest of each transferred file's contents
+	// with the digest recorded by storage. Uploads require an extra request
+	// per file to verify.
+	Verify bool
+
+	// Progress is called each time a file completes. Calls are serialized.
+	Progress func(TransferProgress)
+}
+
+// TransferProgress describes the state of a transfer after a file completes.
+type TransferProgress struct {
+	// Path of the completed file relative to the dataset root.
+	Path string
+
+	// Number of files and bytes transferred so far.
+	Files int64
+	Bytes int64
+
+	// Total number of files and bytes to transfer.
+	TotalFiles int64
+	TotalBytes int64
+}
+
+// ErrChecksumMismatch indicates a transferred file's contents differ from storage.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// transferFile is a single file to copy.
+type transferFile struct {
+	path   string // Relative to the dataset root.
+	size   int64
+	digest []byte // Known digest of the source, if any.
+}
+
+// UploadDataset creates a new dataset from the contents of a local directory
+// and commits it. If name is empty, the dataset is unnamed. On failure, the
+// dataset is left uncommitted and its handle is returned with the error.
+func (c *Client) UploadDataset(
+	ctx context.Context,
+	spec api.DatasetSpec,
+	name string,
+	source string,
+	opts *TransferOptions,
+) (*DatasetHandle, error) {
+	ctx = withOperation(ctx, "Client.UploadDataset")
+
+	spec.FileHeap = true
+	dataset, err := c.CreateDataset(ctx, spec, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dataset.UploadDirectory(ctx, source, opts); err != nil {
+		return dataset, err
+	}
+	if err := dataset.Commit(ctx); err != nil {
+		return dataset, err
+	}
+	return dataset, nil
+}
+
+// UploadDirectory copies all files within a local directory to a dataset.
+// Files are stored at their paths relative to source, replacing existing
+// files of the same name.
+func (h *DatasetHandle) UploadDirectory(
+	ctx context.Context,
+	source string,
+	opts *TransferOptions,
+) error {
+	ctx = withOperation(ctx, "DatasetHandle.UploadDirectory")
+
+	o := transferDefaults(opts)
+
+	var files []transferFile
+	err := filepath.Walk(source, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(source, name)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if o.selects(rel) {
+			files = append(files, transferFile{path: rel, size: info.Size()})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	storage, err := h.RenewingStorage(ctx)
+	if err != nil {
+		return err
+	}
+
+	return transfer(ctx, files, o, func(ctx context.Context, file transferFile) error {
+		return uploadFile(ctx, storage, filepath.Join(source, filepath.FromSlash(file.path)), file, o.Verify)
+	})
+}
+
+func uploadFile(
+	ctx context.Context,
+	storage *RenewingStorage,
+	source string,
+	file transferFile,
+	verify bool,
+) error {
+	hash := sha256.New()
+	err := storage.Do(ctx, func(ref *fileheap.DatasetRef) error {
+		f, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer safeClose(f)
+
+		hash.Reset()
+		return ref.WriteFile(ctx, file.path, io.TeeReader(f, hash), file.size)
+	})
+	if err != nil {
+		return fmt.Errorf("uploading %s: %w", file.path, err)
+	}
+	if !verify {
+		return nil
+	}
+
+	var info *fhapi.FileInfo
+	err = storage.Do(ctx, func(ref *fileheap.DatasetRef) (err error) {
+		info, err = ref.FileInfo(ctx, file.path)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("verifying %s: %w", file.path, err)
+	}
+	if !bytes.Equal(info.Digest, hash.Sum(nil)) {
+		return fmt.Errorf("%s: %w", file.path, ErrChecksumMismatch)
+	}
+	return nil
+}
+
+// DownloadDirectory copies all files within a dataset to a local directory,
+// creating it if necessary. Existing files of the same name are replaced.
+func (h *DatasetHandle) DownloadDirectory(
+	ctx context.Context,
+	target string,
+	opts *TransferOptions,
+) error {
+	ctx = withOperation(ctx, "DatasetHandle.DownloadDirectory")
+
+	o := transferDefaults(opts)
+
+	storage, err := h.RenewingStorage(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Listing is quick, so a single set of credentials suffices.
+	ref, err := storage.Ref(ctx)
+	if err != nil {
+		return err
+	}
+
+	var files []transferFile
+	iter := ref.Files(ctx, nil)
+	for {
+		info, err := iter.Next()
+		if err == fileheap.ErrDone {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if o.selects(info.Path) {
+			files = append(files, transferFile{path: info.Path, size: info.Size, digest: info.Digest})
+		}
+	}
+
+	return transfer(ctx, files, o, func(ctx context.Context, file transferFile) error {
+		dest, err := targetPath(target, file.path)
+		if err != nil {
+			return err
+		}
+		return downloadFile(ctx, storage, dest, file, o.Verify)
+	})
+}
+
+// DownloadFile copies a single file from a dataset to a local path.
+func (h *DatasetHandle) DownloadFile(
+	ctx context.Context,
+	filename string,
+	target string,
+	opts *TransferOptions,
+) error {
+	ctx = withOperation(ctx, "DatasetHandle.DownloadFile")
+
+	o := transferDefaults(opts)
+
+	storage, err := h.RenewingStorage(ctx)
+	if err != nil {
+		return err
+	}
+
+	var info *fhapi.FileInfo
+	err = storage.Do(ctx, func(ref *fileheap.DatasetRef) (err error) {
+		info, err = ref.FileInfo(ctx, filename)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	files := []transferFile{{path: info.Path, size: info.Size, digest: info.Digest}}
+	return transfer(ctx, files, o, func(ctx context.Context, file transferFile) error {
+		return downloadFile(ctx, storage, target, file, o.Verify)
+	})
+}
+
+func downloadFile(
+	ctx context.Context,
+	storage *RenewingStorage,
+	target string,
+	file transferFile,
+	verify bool,
+) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	err := storage.Do(ctx, func(ref *fileheap.DatasetRef) error {
+		r, err := ref.ReadFile(ctx, file.path)
+		if err != nil {
+			return err
+		}
+		defer safeClose(r)
+
+		f, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		defer safeClose(f)
+
+		hash.Reset()
+		if _, err := io.Copy(io.MultiWriter(f, hash), r); err != nil {
+			return err
+		}
+		return f.Close()
+	})
+	if err != nil {
+		return fmt.Errorf("downloading %s: %w", file.path, err)
+	}
+
+	if verify && file.digest != nil && !bytes.Equal(file.digest, hash.Sum(nil)) {
+		return fmt.Errorf("%s: %w", file.path, ErrChecksumMismatch)
+	}
+	return nil
+}
+
+// targetPath joins a dataset file's path to a local directory, rejecting paths
+// which would escape it.
+func targetPath(dir, file string) (string, error) {
+	dest := filepath.Join(dir, filepath.FromSlash(file))
+	rel, err := filepath.Rel(dir, dest)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s: file path is outside of target directory", file)
+	}
+	return dest, nil
+}
+
+func transferDefaults(opts *TransferOptions) TransferOptions {
+	var o TransferOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 8
+	}
+	return o
+}
+
+// selects returns whether a file should be transferred according to the
+// options' include and exclude patterns.
+func (o *TransferOptions) selects(file string) bool {
+	if matchesAny(o.Exclude, file) {
+		return false
+	}
+	return len(o.Include) == 0 || matchesAny(o.Include, file)
+}
+
+// matchesAny returns whether a path or any of its parent directories matches
+// any of the given patterns.
+func matchesAny(patterns []string, file string) bool {
+	for p := file; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		for _, pattern := range patterns {
+			name := p
+			if !strings.Contains(pattern, "/") {
+				name = path.Base(p)
+			}
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// transfer copies files concurrently, stopping at the first error.
+func transfer(
+	ctx context.Context,
+	files []transferFile,
+	opts TransferOptions,
+	copyFile func(context.Context, transferFile) error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var progress TransferProgress
+	for _, f := range files {
+		progress.TotalFiles++
+		progress.TotalBytes += f.size
+	}
+
+	queue := make(chan transferFile)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range queue {
+				err := copyFile(ctx, file)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+				} else {
+					progress.Path = file.path
+					progress.Files++
+					progress.Bytes += file.size
+					if opts.Progress != nil {
+						opts.Progress(progress)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+send:
+	for _, file := range files {
+		select {
+		case queue <- file:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
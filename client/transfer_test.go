@@ -0,0 +1,373 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fhapi "github.com/beaker/fileheap/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beaker/client/api"
+)
+
+// fakeStorage serves dataset "ds1" and its backing file storage. Each time the
+// dataset is fetched, it issues a new storage token which expires after ttl.
+type fakeStorage struct {
+	t      *testing.T
+	client *Client
+	ttl    time.Duration
+
+	mu       sync.Mutex
+	files    map[string][]byte
+	token    string // Token which storage accepts.
+	renewals int    // Number of tokens issued.
+
+	// Optional hook to alter responses to file requests. It returns true if
+	// it handled the request.
+	handle func(w http.ResponseWriter, r *http.Request, file string) bool
+}
+
+func newFakeStorage(t *testing.T, files map[string]string) *fakeStorage {
+	s := &fakeStorage{t: t, ttl: time.Hour, files: map[string][]byte{}}
+	for name, content := range files {
+		s.files[name] = []byte(content)
+	}
+
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, "")
+	require.NoError(t, err)
+	s.client = client
+	return s
+}
+
+func (s *fakeStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v3/datasets/ds1" {
+		s.mu.Lock()
+		s.renewals++
+		s.token = "token-" + strconv.Itoa(s.renewals)
+		dataset := api.Dataset{ID: "ds1", Storage: &api.DatasetStorage{
+			Address:      "http://" + r.Host,
+			ID:           "fh1",
+			Token:        s.token,
+			TokenExpires: time.Now().Add(s.ttl),
+		}}
+		s.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(dataset)
+		return
+	}
+
+	s.mu.Lock()
+	authorized := r.Header.Get("Authorization") == "Bearer "+s.token
+	handle := s.handle
+	s.mu.Unlock()
+	if !authorized {
+		writeStorageError(w, http.StatusUnauthorized)
+		return
+	}
+
+	const filesPrefix = "/datasets/fh1/files/"
+	file := strings.TrimPrefix(r.URL.Path, filesPrefix)
+	if handle != nil && file != r.URL.Path && handle(w, r, file) {
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/datasets/fh1/manifest":
+		s.mu.Lock()
+		var page fhapi.ManifestPage
+		for name, content := range s.files {
+			digest := sha256.Sum256(content)
+			page.Files = append(page.Files, fhapi.FileInfo{Path: name, Size: int64(len(content)), Digest: digest[:]})
+		}
+		s.mu.Unlock()
+		sort.Slice(page.Files, func(i, j int) bool { return page.Files[i].Path < page.Files[j].Path })
+		_ = json.NewEncoder(w).Encode(page)
+
+	case strings.HasPrefix(r.URL.Path, filesPrefix):
+		s.mu.Lock()
+		content, ok := s.files[file]
+		s.mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(s.t, err)
+			s.mu.Lock()
+			s.files[file] = body
+			s.mu.Unlock()
+		case http.MethodHead:
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			digest := sha256.Sum256(content)
+			w.Header().Set(fhapi.HeaderDigest, fhapi.EncodeDigest(digest[:]))
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		case http.MethodGet:
+			if !ok {
+				writeStorageError(w, http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(content)
+		}
+
+	default:
+		writeStorageError(w, http.StatusNotFound)
+	}
+}
+
+func writeStorageError(w http.ResponseWriter, code int) {
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(fhapi.Error{Code: code, Message: http.StatusText(code)})
+}
+
+func (s *fakeStorage) setHandler(handle func(w http.ResponseWriter, r *http.Request, file string) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handle = handle
+}
+
+// writeFiles creates files within a new temporary directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "transfer")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	for name, content := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0755))
+		require.NoError(t, ioutil.WriteFile(name, []byte(content), 0644))
+	}
+	return dir
+}
+
+// readFiles reads every file within a directory by slash-separated path.
+func readFiles(t *testing.T, dir string) map[string]string {
+	files := map[string]string{}
+	err := filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := ioutil.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		files[filepath.ToSlash(rel)] = string(content)
+		return err
+	})
+	require.NoError(t, err)
+	return files
+}
+
+var transferFiles = map[string]string{
+	"a.txt":          "alpha",
+	"data/b.csv":     "1,2,3\n",
+	"data/deep/c.md": "# gamma",
+}
+
+func TestUploadDirectory(t *testing.T) {
+	storage := newFakeStorage(t, nil)
+	source := writeFiles(t, transferFiles)
+
+	var last TransferProgress
+	calls := 0
+	err := storage.client.Dataset("ds1").UploadDirectory(context.Background(), source, &TransferOptions{
+		Verify: true,
+		Progress: func(p TransferProgress) {
+			calls++
+			last = p
+		},
+	})
+	require.NoError(t, err)
+
+	uploaded := map[string]string{}
+	for name, content := range storage.files {
+		uploaded[name] = string(content)
+	}
+	assert.Equal(t, transferFiles, uploaded)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, TransferProgress{Path: last.Path, Files: 3, Bytes: 18, TotalFiles: 3, TotalBytes: 18}, last)
+}
+
+func TestUploadDirectoryChecksumMismatch(t *testing.T) {
+	storage := newFakeStorage(t, nil)
+	source := writeFiles(t, transferFiles)
+
+	// Storage records a digest which doesn't match what was uploaded.
+	storage.setHandler(func(w http.ResponseWriter, r *http.Request, file string) bool {
+		if r.Method != http.MethodHead || file != "data/b.csv" {
+			return false
+		}
+		digest := sha256.Sum256([]byte("corrupted"))
+		w.Header().Set(fhapi.HeaderDigest, fhapi.EncodeDigest(digest[:]))
+		return true
+	})
+
+	err := storage.client.Dataset("ds1").UploadDirectory(context.Background(), source, &TransferOptions{Verify: true})
+	assert.True(t, errors.Is(err, ErrChecksumMismatch), "unexpected error: %v", err)
+	assert.Contains(t, err.Error(), "data/b.csv")
+}
+
+func TestDownloadDirectory(t *testing.T) {
+	storage := newFakeStorage(t, transferFiles)
+	target := writeFiles(t, nil)
+
+	var last TransferProgress
+	err := storage.client.Dataset("ds1").DownloadDirectory(context.Background(), target, &TransferOptions{
+		Verify:   true,
+		Exclude:  []string{"deep"},
+		Progress: func(p TransferProgress) { last = p },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a.txt": "alpha", "data/b.csv": "1,2,3\n"}, readFiles(t, target))
+	assert.Equal(t, TransferProgress{Path: last.Path, Files: 2, Bytes: 11, TotalFiles: 2, TotalBytes: 11}, last)
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	storage := newFakeStorage(t, transferFiles)
+
+	// Content is corrupted in transit, so it no longer matches the manifest.
+	storage.setHandler(func(w http.ResponseWriter, r *http.Request, file string) bool {
+		if r.Method != http.MethodGet || file != "a.txt" {
+			return false
+		}
+		_, _ = w.Write([]byte("alpHa"))
+		return true
+	})
+
+	dataset := storage.client.Dataset("ds1")
+	err := dataset.DownloadDirectory(context.Background(), writeFiles(t, nil), &TransferOptions{Verify: true})
+	assert.True(t, errors.Is(err, ErrChecksumMismatch), "unexpected error: %v", err)
+
+	target := filepath.Join(writeFiles(t, nil), "a.txt")
+	err = dataset.DownloadFile(context.Background(), "a.txt", target, &TransferOptions{Verify: true})
+	assert.True(t, errors.Is(err, ErrChecksumMismatch), "unexpected error: %v", err)
+
+	// Without verification, the corrupted content is accepted.
+	require.NoError(t, dataset.DownloadFile(context.Background(), "a.txt", target, nil))
+	content, err := ioutil.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "alpHa", string(content))
+}
+
+func TestDownloadFile(t *testing.T) {
+	storage := newFakeStorage(t, transferFiles)
+	target := filepath.Join(writeFiles(t, nil), "nested", "c.md")
+
+	var progress []TransferProgress
+	err := storage.client.Dataset("ds1").DownloadFile(context.Background(), "data/deep/c.md", target, &TransferOptions{
+		Verify:   true,
+		Progress: func(p TransferProgress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+
+	content, err := ioutil.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "# gamma", string(content))
+	assert.Equal(t, []TransferProgress{
+		{Path: "data/deep/c.md", Files: 1, Bytes: 7, TotalFiles: 1, TotalBytes: 7},
+	}, progress)
+
+	err = storage.client.Dataset("ds1").DownloadFile(context.Background(), "missing", target, nil)
+	assert.Error(t, err)
+}
+
+func TestTransferFailureCancelsWorkers(t *testing.T) {
+	files := map[string]string{"0-bad": "x"}
+	for i := 1; i < 20; i++ {
+		files[strconv.Itoa(i)+"-slow"] = "y"
+	}
+	storage := newFakeStorage(t, files)
+
+	var mu sync.Mutex
+	requested, canceled := 0, 0
+	storage.setHandler(func(w http.ResponseWriter, r *http.Request, file string) bool {
+		if r.Method != http.MethodGet {
+			return false
+		}
+		mu.Lock()
+		requested++
+		mu.Unlock()
+
+		if file == "0-bad" {
+			writeStorageError(w, http.StatusBadRequest)
+			return true
+		}
+
+		// Slow files only complete if their request isn't canceled.
+		select {
+		case <-r.Context().Done():
+			mu.Lock()
+			canceled++
+			mu.Unlock()
+		case <-time.After(10 * time.Second):
+		}
+		return false
+	})
+
+	start := time.Now()
+	err := storage.client.Dataset("ds1").DownloadDirectory(context.Background(), writeFiles(t, nil), &TransferOptions{
+		Concurrency: 4,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "0-bad")
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second), "workers should be canceled")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Less(t, requested, len(files), "no more files should be requested after a failure")
+}
+
+func TestTransferSelects(t *testing.T) {
+	opts := TransferOptions{
+		Include: []string{"data", "*.json"},
+		Exclude: []string{"data/tmp", "*.bak"},
+	}
+
+	cases := map[string]bool{
+		"config.json":          true,
+		"nested/config.json":   true,
+		"data/train.csv":       true,
+		"data/tmp/scratch.csv": false,
+		"data/train.csv.bak":   false,
+		"data/old.bak":         false,
+		"README.md":            false,
+		"nested/data/x.csv":    true,
+	}
+	for file, expected := range cases {
+		assert.Equal(t, expected, opts.selects(file), file)
+	}
+
+	assert.True(t, (&TransferOptions{}).selects("anything/at/all"))
+}
+
+func TestTargetPath(t *testing.T) {
+	dir := filepath.FromSlash("/tmp/target")
+
+	dest, err := targetPath(dir, "a/b.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/tmp/target/a/b.txt"), dest)
+
+	_, err = targetPath(dir, "../escape.txt")
+	assert.Error(t, err)
+
+	_, err = targetPath(dir, "a/../../escape.txt")
+	assert.Error(t, err)
+}
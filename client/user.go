@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 
 	"github.com/beaker/client/api"
 )
@@ -13,13 +14,26 @@ import (
 func (c *Client) ListUsers(
 	ctx context.Context,
 	cursor string,
+) ([]api.UserDetail, string, error) {
+	ctx = withOperation(ctx, "Client.ListUsers")
+	return c.listUsers(ctx, cursor, 0)
+}
+
+func (c *Client) listUsers(
+	ctx context.Context,
+	cursor string,
+	limit int,
 ) ([]api.UserDetail, string, error) {
 	query := url.Values{}
 	query.Add("cursor", cursor)
+	if limit > 0 {
+		query.Add("limit", strconv.Itoa(limit))
+	}
 	resp, err := c.sendRetryableRequest(ctx, http.MethodGet, "/api/v3/admin/users", query, nil)
 	if err != nil {
 		return nil, "", err
 	}
+	defer safeClose(resp.Body)
 
 	var result api.UserPage
 	if err := parseResponse(resp, &result); err != nil {
@@ -46,6 +60,8 @@ func (h *UserHandle) Ref() string {
 
 // Get retrieves a user's details.
 func (h *UserHandle) Get(ctx context.Context) (*api.UserDetail, error) {
+	ctx = withOperation(ctx, "UserHandle.Get")
+
 	uri := path.Join("/api/v3/users", url.PathEscape(h.ref))
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, uri, nil, nil)
 	if err != nil {
@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"path"
 	"strconv"
-	"time"
 
 	"github.com/beaker/client/api"
 )
@@ -19,6 +18,12 @@ func (c *Client) CreateWorkspace(
 	ctx context.Context,
 	spec api.WorkspaceSpec,
 ) (*WorkspaceHandle, error) {
+	ctx = withOperation(ctx, "Client.CreateWorkspace")
+
+	if spec.Organization == "" {
+		spec.Organization = c.defaultOrg
+	}
+
 	resp, err := c.sendRetryableRequest(ctx, http.MethodPost, "/api/v3/workspaces", nil, spec)
 	if err != nil {
 		return nil, err
@@ -36,21 +41,32 @@ func (c *Client) CreateWorkspace(
 type ListWorkspaceOptions struct {
 	Archived *bool
 	Cursor   string
+	Limit    int64
 	Text     string
 }
 
+// ListWorkspaces lists workspaces in an organization. If org is empty, the
+// client's default organization is used.
 func (c *Client) ListWorkspaces(
 	ctx context.Context,
 	org string,
 	opts *ListWorkspaceOptions,
 ) ([]api.Workspace, string, error) {
+	ctx = withOperation(ctx, "Client.ListWorkspaces")
+
 	if opts == nil {
 		opts = &ListWorkspaceOptions{}
 	}
+	if org == "" {
+		org = c.defaultOrg
+	}
 
 	query := url.Values{}
 	query.Add("org", org)
 	query.Add("cursor", opts.Cursor)
+	if opts.Limit > 0 {
+		query.Add("limit", strconv.FormatInt(opts.Limit, 10))
+	}
 	if opts.Archived != nil {
 		query.Add("archived", strconv.FormatBool(*opts.Archived))
 	}
@@ -74,7 +90,7 @@ func (c *Client) ListWorkspaces(
 
 // Workspace gets a handle for a workspace by name or ID. The reference is not resolved.
 func (c *Client) Workspace(reference string) *WorkspaceHandle {
-	return &WorkspaceHandle{client: c, ref: reference}
+	return &WorkspaceHandle{client: c, ref: c.qualifyWorkspace(reference)}
 }
 
 type WorkspaceHandle struct {
@@ -89,6 +105,13 @@ func (h *WorkspaceHandle) Ref() string {
 
 // Get retrieves a task's details.
 func (h *WorkspaceHandle) Get(ctx context.Context) (*api.Workspace, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.Get")
+
+	var cached api.Workspace
+	if h.client.cache.get(workspaceKind, h.ref, &cached) {
+		return &cached, nil
+	}
+
 	path := path.Join("/api/v3/workspaces", url.PathEscape(h.ref))
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -101,10 +124,31 @@ func (h *WorkspaceHandle) Get(ctx context.Context) (*api.Workspace, error) {
 		return nil, err
 	}
 
+	h.client.cache.put(workspaceKind, workspace.ID, &workspace, h.ref, workspace.FullName)
 	return &workspace, nil
 }
 
+// Resolve returns a handle pinned to the workspace's ID, so it continues to
+// refer to the same workspace if its name is changed or reused.
+func (h *WorkspaceHandle) Resolve(ctx context.Context) (*WorkspaceHandle, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.Resolve")
+
+	if isID(h.ref) {
+		return h, nil
+	}
+
+	workspace, err := h.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &WorkspaceHandle{client: h.client, ref: workspace.ID}, nil
+}
+
 func (h *WorkspaceHandle) Transfer(ctx context.Context, ids ...string) error {
+	ctx = withOperation(ctx, "WorkspaceHandle.Transfer")
+
+	defer h.client.cache.invalidate(workspaceKind, h.ref)
+	defer h.client.cache.invalidateIDs(ids...)
 	body := api.WorkspaceTransferSpec{IDs: ids}
 	path := path.Join("/api/v3/workspaces", url.PathEscape(h.ref), "transfer")
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPost, path, nil, body)
@@ -117,6 +161,7 @@ func (h *WorkspaceHandle) Transfer(ctx context.Context, ids ...string) error {
 
 type ListDatasetOptions struct {
 	Cursor        string
+	Limit         int64
 	ResultsOnly   *bool
 	CommittedOnly *bool
 	Text          string
@@ -126,12 +171,17 @@ func (h *WorkspaceHandle) Datasets(
 	ctx context.Context,
 	opts *ListDatasetOptions,
 ) ([]api.Dataset, string, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.Datasets")
+
 	if opts == nil {
 		opts = &ListDatasetOptions{}
 	}
 
 	query := url.Values{}
 	query.Add("cursor", opts.Cursor)
+	if opts.Limit > 0 {
+		query.Add("limit", strconv.FormatInt(opts.Limit, 10))
+	}
 	if opts.ResultsOnly != nil {
 		query.Add("results", strconv.FormatBool(*opts.ResultsOnly))
 	}
@@ -177,6 +227,8 @@ func (h *WorkspaceHandle) CreateExperimentRaw(
 	spec io.Reader,
 	opts *ExperimentOpts,
 ) (*api.Experiment, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.CreateExperimentRaw")
+
 	var query url.Values
 	if opts != nil && opts.Name != "" {
 		query = url.Values{"name": {opts.Name}}
@@ -193,10 +245,7 @@ func (h *WorkspaceHandle) CreateExperimentRaw(
 		req.Header.Set(api.HeaderAuthor, opts.AuthorToken)
 	}
 
-	resp, err := newRetryableClient(&http.Client{
-		Timeout:       30 * time.Second,
-		CheckRedirect: copyRedirectHeader,
-	}, h.client.HTTPResponseHook).Do(req.WithContext(ctx))
+	resp, err := h.client.do(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -216,6 +265,8 @@ func (h *WorkspaceHandle) CreateExperiment(
 	spec *api.ExperimentSpecV2,
 	opts *ExperimentOpts,
 ) (*api.Experiment, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.CreateExperiment")
+
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(spec); err != nil {
 		return nil, err
@@ -226,6 +277,7 @@ func (h *WorkspaceHandle) CreateExperiment(
 
 type ListExperimentOptions struct {
 	Cursor string
+	Limit  int64
 	Text   string
 }
 
@@ -233,12 +285,17 @@ func (h *WorkspaceHandle) Experiments(
 	ctx context.Context,
 	opts *ListExperimentOptions,
 ) ([]api.Experiment, string, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.Experiments")
+
 	if opts == nil {
 		opts = &ListExperimentOptions{}
 	}
 
 	query := url.Values{}
 	query.Add("cursor", opts.Cursor)
+	if opts.Limit > 0 {
+		query.Add("limit", strconv.FormatInt(opts.Limit, 10))
+	}
 	if opts.Text != "" {
 		query.Add("q", opts.Text)
 	}
@@ -260,6 +317,7 @@ func (h *WorkspaceHandle) Experiments(
 
 type ListGroupOptions struct {
 	Cursor string
+	Limit  int64
 	Text   string
 }
 
@@ -267,12 +325,17 @@ func (h *WorkspaceHandle) Groups(
 	ctx context.Context,
 	opts *ListGroupOptions,
 ) ([]api.Group, string, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.Groups")
+
 	if opts == nil {
 		opts = &ListGroupOptions{}
 	}
 
 	query := url.Values{}
 	query.Add("cursor", opts.Cursor)
+	if opts.Limit > 0 {
+		query.Add("limit", strconv.FormatInt(opts.Limit, 10))
+	}
 	if opts.Text != "" {
 		query.Add("q", opts.Text)
 	}
@@ -294,6 +357,7 @@ func (h *WorkspaceHandle) Groups(
 
 type ListImageOptions struct {
 	Cursor string
+	Limit  int64
 	Text   string
 }
 
@@ -301,12 +365,17 @@ func (h *WorkspaceHandle) Images(
 	ctx context.Context,
 	opts *ListImageOptions,
 ) ([]api.Image, string, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.Images")
+
 	if opts == nil {
 		opts = &ListImageOptions{}
 	}
 
 	query := url.Values{}
 	query.Add("cursor", opts.Cursor)
+	if opts.Limit > 0 {
+		query.Add("limit", strconv.FormatInt(opts.Limit, 10))
+	}
 	if opts.Text != "" {
 		query.Add("q", opts.Text)
 	}
@@ -329,17 +398,26 @@ func (h *WorkspaceHandle) Images(
 // SetName sets a workspace's name.
 // References to the workspace's contents using the old name will stop working.
 func (h *WorkspaceHandle) SetName(ctx context.Context, name string) error {
+	ctx = withOperation(ctx, "WorkspaceHandle.SetName")
+
+	defer h.client.cache.invalidate(workspaceKind, h.ref)
 	return h.patchWorkspace(ctx, api.WorkspacePatchSpec{Name: &name})
 }
 
 // SetDescription sets a workspace's description.
 func (h *WorkspaceHandle) SetDescription(ctx context.Context, desc string) error {
+	ctx = withOperation(ctx, "WorkspaceHandle.SetDescription")
+
+	defer h.client.cache.invalidate(workspaceKind, h.ref)
 	return h.patchWorkspace(ctx, api.WorkspacePatchSpec{Description: &desc})
 }
 
 // SetArchived sets the archival status of a workspace.
 // Archived workspaces are read-only.
 func (h *WorkspaceHandle) SetArchived(ctx context.Context, archive bool) error {
+	ctx = withOperation(ctx, "WorkspaceHandle.SetArchived")
+
+	defer h.client.cache.invalidate(workspaceKind, h.ref)
 	return h.patchWorkspace(ctx, api.WorkspacePatchSpec{Archive: &archive})
 }
 
@@ -354,6 +432,8 @@ func (h *WorkspaceHandle) patchWorkspace(ctx context.Context, spec api.Workspace
 }
 
 func (h *WorkspaceHandle) Permissions(ctx context.Context) (*api.WorkspacePermissionSummary, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.Permissions")
+
 	path := path.Join("/api/v3/workspaces", url.PathEscape(h.ref), "auth")
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -370,6 +450,9 @@ func (h *WorkspaceHandle) Permissions(ctx context.Context) (*api.WorkspacePermis
 }
 
 func (h *WorkspaceHandle) SetPermissions(ctx context.Context, patch api.WorkspacePermissionPatch) error {
+	ctx = withOperation(ctx, "WorkspaceHandle.SetPermissions")
+
+	defer h.client.cache.invalidate(workspaceKind, h.ref)
 	path := path.Join("/api/v3/workspaces", url.PathEscape(h.ref), "auth")
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodPatch, path, nil, patch)
 	if err != nil {
@@ -380,6 +463,8 @@ func (h *WorkspaceHandle) SetPermissions(ctx context.Context, patch api.Workspac
 }
 
 func (h *WorkspaceHandle) ListSecrets(ctx context.Context) ([]api.Secret, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.ListSecrets")
+
 	path := path.Join("/api/v3/workspaces", url.PathEscape(h.ref), "secrets")
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -396,6 +481,8 @@ func (h *WorkspaceHandle) ListSecrets(ctx context.Context) ([]api.Secret, error)
 }
 
 func (h *WorkspaceHandle) GetSecret(ctx context.Context, name string) (*api.Secret, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.GetSecret")
+
 	path := path.Join("/api/v3/workspaces", url.PathEscape(h.ref), "secrets", name)
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
@@ -412,6 +499,8 @@ func (h *WorkspaceHandle) GetSecret(ctx context.Context, name string) (*api.Secr
 }
 
 func (h *WorkspaceHandle) DeleteSecret(ctx context.Context, name string) error {
+	ctx = withOperation(ctx, "WorkspaceHandle.DeleteSecret")
+
 	path := path.Join("/api/v3/workspaces", url.PathEscape(h.ref), "secrets", name)
 	resp, err := h.client.sendRetryableRequest(ctx, http.MethodDelete, path, nil, nil)
 	if err != nil {
@@ -426,16 +515,15 @@ func (h *WorkspaceHandle) PutSecret(
 	name string,
 	value []byte,
 ) (*api.Secret, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.PutSecret")
+
 	path := path.Join("/api/v3/workspaces", url.PathEscape(h.ref), "secrets", url.PathEscape(name), "value")
 	req, err := h.client.newRetryableRequest(http.MethodPut, path, nil, bytes.NewReader(value))
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := newRetryableClient(&http.Client{
-		Timeout:       30 * time.Second,
-		CheckRedirect: copyRedirectHeader,
-	}, h.client.HTTPResponseHook).Do(req.WithContext(ctx))
+	resp, err := h.client.do(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -450,16 +538,15 @@ func (h *WorkspaceHandle) PutSecret(
 }
 
 func (h *WorkspaceHandle) ReadSecret(ctx context.Context, name string) ([]byte, error) {
+	ctx = withOperation(ctx, "WorkspaceHandle.ReadSecret")
+
 	path := path.Join("/api/v3/workspaces", url.PathEscape(h.ref), "secrets", url.PathEscape(name), "value")
 	req, err := h.client.newRetryableRequest(http.MethodGet, path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := newRetryableClient(&http.Client{
-		Timeout:       30 * time.Second,
-		CheckRedirect: copyRedirectHeader,
-	}, h.client.HTTPResponseHook).Do(req.WithContext(ctx))
+	resp, err := h.client.do(ctx, req)
 	if err != nil {
 		return nil, err
 	}
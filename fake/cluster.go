@@ -0,0 +1,359 @@
+package fake
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/beaker/client/api"
+)
+
+type node struct {
+	api.Node
+	cluster string // ID
+}
+
+func (s *Server) findCluster(ref string) (*api.Cluster, error) {
+	for _, c := range s.clusters {
+		if c.ID == ref || c.FullName == ref {
+			return c, nil
+		}
+	}
+	return nil, notFound("cluster", ref)
+}
+
+func (s *Server) findNode(id string) (*node, error) {
+	for _, n := range s.nodes {
+		if n.ID == id {
+			return n, nil
+		}
+	}
+	return nil, notFound("node", id)
+}
+
+// targets returns whether an execution is meant to run on a cluster.
+func (x *execution) targets(c *api.Cluster) bool {
+	return x.Spec.Context.Cluster == c.ID || x.Spec.Context.Cluster == c.FullName
+}
+
+func (s *Server) createCluster(r *http.Request, args []string) (interface{}, error) {
+	account, err := s.findAccount(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var spec api.ClusterSpec
+	if err := decodeBody(r, &spec); err != nil {
+		return nil, err
+	}
+	if spec.Name == "" {
+		return nil, errorf(http.StatusBadRequest, "cluster name is required")
+	}
+	if spec.Capacity < 0 {
+		return nil, errorf(http.StatusBadRequest, "cluster capacity must not be negative")
+	}
+
+	name := fullName(account, spec.Name)
+	if _, err := s.findCluster(name); err == nil {
+		return nil, conflict("cluster", name)
+	}
+
+	c := &api.Cluster{
+		ID:          s.newID(),
+		Name:        spec.Name,
+		FullName:    name,
+		Created:     now(),
+		Protected:   spec.Protected,
+		Autoscale:   spec.Capacity > 0,
+		Capacity:    spec.Capacity,
+		Preemptible: spec.Preemptible,
+		Status:      api.ClusterActive,
+	}
+	if spec.Spec != nil {
+		c.NodeSpec = *spec.Spec
+	}
+	s.clusters = append(s.clusters, c)
+	return *c, nil
+}
+
+func (s *Server) listClusters(r *http.Request, args []string) (interface{}, error) {
+	account, err := s.findAccount(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []api.Cluster
+	for _, c := range s.clusters {
+		if strings.HasPrefix(c.FullName, account+"/") {
+			matches = append(matches, *c)
+		}
+	}
+
+	start, end, next, err := paginate(r, len(matches))
+	if err != nil {
+		return nil, err
+	}
+	return api.ClusterPage{Data: append([]api.Cluster{}, matches[start:end]...), NextCursor: next}, nil
+}
+
+func (s *Server) getCluster(r *http.Request, args []string) (interface{}, error) {
+	c, err := s.findCluster(args[0] + "/" + args[1])
+	if err != nil {
+		return nil, err
+	}
+	return *c, nil
+}
+
+func (s *Server) patchCluster(r *http.Request, args []string) (interface{}, error) {
+	c, err := s.findCluster(args[0] + "/" + args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var patch api.ClusterPatch
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+
+	if patch.Capacity != nil {
+		if !c.Autoscale {
+			return nil, errorf(http.StatusBadRequest, "capacity of cluster %q is fixed", c.FullName)
+		}
+		c.Capacity = *patch.Capacity
+	}
+	if patch.Valid != nil {
+		if *patch.Valid {
+			t := now()
+			c.Validated = &t
+			c.Status = api.ClusterActive
+		} else {
+			c.Status = api.ClusterFailed
+		}
+	}
+	if patch.StatusMessage != nil {
+		c.StatusMessage = *patch.StatusMessage
+	}
+	if patch.NodeShape != nil {
+		c.NodeShape = patch.NodeShape
+	}
+	if patch.NodeCost != nil {
+		c.NodeCost = patch.NodeCost
+	}
+	return *c, nil
+}
+
+func (s *Server) terminateCluster(r *http.Request, args []string) (interface{}, error) {
+	c, err := s.findCluster(args[0] + "/" + args[1])
+	if err != nil {
+		return nil, err
+	}
+	if c.Protected {
+		return nil, errorf(http.StatusForbidden, "cluster %q is protected", c.FullName)
+	}
+	c.Status = api.ClusterTerminated
+	return nil, nil
+}
+
+func (s *Server) createNode(r *http.Request, args []string) (interface{}, error) {
+	c, err := s.findCluster(args[0] + "/" + args[1])
+	if err != nil {
+		return nil, err
+	}
+	if c.Status == api.ClusterTerminated {
+		return nil, errorf(http.StatusForbidden, "cluster %q is terminated", c.FullName)
+	}
+
+	var spec api.NodeSpec
+	if err := decodeBody(r, &spec); err != nil {
+		return nil, err
+	}
+	if spec.Hostname == "" {
+		return nil, errorf(http.StatusBadRequest, "hostname is required")
+	}
+
+	n := &node{
+		Node:    api.Node{ID: s.newID(), Hostname: spec.Hostname, Created: now(), Limits: spec.Limits},
+		cluster: c.ID,
+	}
+	s.nodes = append(s.nodes, n)
+	return n.Node, nil
+}
+
+func (s *Server) listClusterNodes(r *http.Request, args []string) (interface{}, error) {
+	c, err := s.findCluster(args[0] + "/" + args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	page := api.NodePage{Data: []api.Node{}}
+	for _, n := range s.nodes {
+		if n.cluster == c.ID {
+			page.Data = append(page.Data, n.Node)
+		}
+	}
+	return page, nil
+}
+
+func (s *Server) listClusterExecutions(r *http.Request, args []string) (interface{}, error) {
+	c, err := s.findCluster(args[0] + "/" + args[1])
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := parseBool(r, "scheduled")
+	if err != nil {
+		return nil, err
+	}
+
+	result := api.Executions{Data: []api.Execution{}}
+	for _, x := range s.executions {
+		if !x.targets(c) || x.State.Finalized != nil {
+			continue
+		}
+		if scheduled != nil && (x.State.Scheduled != nil) != *scheduled {
+			continue
+		}
+		result.Data = append(result.Data, x.Execution)
+	}
+	return result, nil
+}
+
+func (s *Server) patchClusterExecution(r *http.Request, args []string) (interface{}, error) {
+	c, err := s.findCluster(args[0] + "/" + args[1])
+	if err != nil {
+		return nil, err
+	}
+	x, err := s.findExecution(args[2])
+	if err != nil {
+		return nil, err
+	}
+	if !x.targets(c) {
+		return nil, notFound("execution", args[2])
+	}
+
+	var patch api.ExecutionPatchSpec
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+
+	switch patch.Priority {
+	case "":
+	case api.LowPriority, api.NormalPriority, api.HighPriority, api.UrgentPriority:
+		x.Priority = patch.Priority
+	default:
+		return nil, errorf(http.StatusBadRequest, "invalid priority %q", patch.Priority)
+	}
+	return nil, nil
+}
+
+func (s *Server) getNode(r *http.Request, args []string) (interface{}, error) {
+	n, err := s.findNode(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return n.Node, nil
+}
+
+func (s *Server) patchNode(r *http.Request, args []string) (interface{}, error) {
+	n, err := s.findNode(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var patch api.NodePatchSpec
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+
+	if patch.TTL != nil {
+		if *patch.TTL == "" {
+			n.Expiry = nil
+		} else {
+			ttl, err := time.ParseDuration(*patch.TTL)
+			if err != nil {
+				return nil, errorf(http.StatusBadRequest, "invalid TTL %q", *patch.TTL)
+			}
+			expiry := now().Add(ttl)
+			n.Expiry = &expiry
+		}
+	}
+	if patch.Cordoned != nil {
+		if *patch.Cordoned && n.Cordoned == nil {
+			t := now()
+			n.Cordoned = &t
+		} else if !*patch.Cordoned {
+			n.Cordoned = nil
+		}
+	}
+	if patch.Hostname != nil {
+		n.Hostname = *patch.Hostname
+	}
+	if patch.Limits != nil {
+		n.Limits = patch.Limits
+	}
+	return nil, nil
+}
+
+func (s *Server) deleteNode(r *http.Request, args []string) (interface{}, error) {
+	n, err := s.findNode(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := s.nodes[:0]
+	for _, other := range s.nodes {
+		if other != n {
+			nodes = append(nodes, other)
+		}
+	}
+	s.nodes = nodes
+	return nil, nil
+}
+
+func (s *Server) listNodeExecutions(r *http.Request, args []string) (interface{}, error) {
+	n, err := s.findNode(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := api.Executions{Data: []api.Execution{}}
+	for _, x := range s.executions {
+		if x.Node == n.ID && x.State.Finalized == nil {
+			result.Data = append(result.Data, x.Execution)
+		}
+	}
+	return result, nil
+}
+
+// assignExecutions schedules pending executions on a node. Resource requests
+// are not considered; every pending execution on the node's cluster is
+// assigned unless the node is cordoned.
+func (s *Server) assignExecutions(r *http.Request, args []string) (interface{}, error) {
+	n, err := s.findNode(args[0])
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.findCluster(n.cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources api.NodeResources
+	if err := decodeBody(r, &resources); err != nil {
+		return nil, err
+	}
+
+	result := api.Executions{Data: []api.Execution{}}
+	if n.Cordoned != nil {
+		return result, nil
+	}
+	for _, x := range s.executions {
+		if !x.targets(c) || x.Node != "" || x.State.Finalized != nil {
+			continue
+		}
+		t := now()
+		x.Node = n.ID
+		x.State.Scheduled = &t
+		result.Data = append(result.Data, x.Execution)
+	}
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package fake
+
+import (
+	"net/http"
+
+	"github.com/beaker/client/api"
+)
+
+func (s *Server) findDataset(ref string) (int, error) {
+	name := s.itemName(ref)
+	for i, d := range s.datasets {
+		if d.ID == ref || (d.FullName != "" && d.FullName == name) {
+			return i, nil
+		}
+	}
+	return 0, notFound("dataset", ref)
+}
+
+// viewDataset returns a dataset as the service presents it. File storage isn't
+// emulated, so storage details are always omitted.
+func viewDataset(d *api.Dataset) api.Dataset {
+	view := *d
+	view.Storage = nil
+	return view
+}
+
+// targetWorkspace resolves the workspace in which to create an item.
+func (s *Server) targetWorkspace(ref string) (*workspace, error) {
+	if ref == "" {
+		return nil, errorf(http.StatusBadRequest, "workspace is required")
+	}
+	w, err := s.findWorkspace(ref)
+	if err != nil {
+		return nil, err
+	}
+	if w.Archived {
+		return nil, errorf(http.StatusForbidden, "workspace %q is archived", w.FullName)
+	}
+	return w, nil
+}
+
+func (s *Server) createDataset(r *http.Request, args []string) (interface{}, error) {
+	var spec api.DatasetSpec
+	if err := decodeBody(r, &spec); err != nil {
+		return nil, err
+	}
+	w, err := s.targetWorkspace(spec.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	name := r.URL.Query().Get("name")
+	if _, err := s.findDataset(fullName(s.User.Name, name)); name != "" && err == nil {
+		return nil, conflict("dataset", fullName(s.User.Name, name))
+	}
+
+	d := &api.Dataset{
+		ID:          s.newID(),
+		Name:        name,
+		FullName:    fullName(s.User.Name, name),
+		Owner:       s.User.Identity,
+		Author:      s.User.Identity,
+		Workspace:   w.ref(),
+		Created:     now(),
+		Description: spec.Description,
+	}
+	s.datasets = append(s.datasets, d)
+	return viewDataset(d), nil
+}
+
+func (s *Server) getDataset(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findDataset(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return viewDataset(s.datasets[i]), nil
+}
+
+func (s *Server) patchDataset(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findDataset(args[0])
+	if err != nil {
+		return nil, err
+	}
+	d := s.datasets[i]
+
+	var patch api.DatasetPatchSpec
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil {
+		name := fullName(d.Author.Name, *patch.Name)
+		if j, err := s.findDataset(name); name != "" && err == nil && j != i {
+			return nil, conflict("dataset", name)
+		}
+		d.Name, d.FullName = *patch.Name, name
+	}
+	if patch.Description != nil {
+		d.Description = *patch.Description
+	}
+	if patch.Commit && d.Committed.IsZero() {
+		d.Committed = now()
+	}
+	return viewDataset(d), nil
+}
+
+func (s *Server) deleteDataset(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findDataset(args[0])
+	if err != nil {
+		return nil, err
+	}
+	s.datasets = append(s.datasets[:i], s.datasets[i+1:]...)
+	return nil, nil
+}
@@ -0,0 +1,215 @@
+package fake
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beaker/client/api"
+)
+
+type execution struct {
+	api.Execution
+	logs    []byte
+	metrics map[string]interface{}
+}
+
+func (s *Server) findExecution(id string) (*execution, error) {
+	for _, x := range s.executions {
+		if x.ID == id {
+			return x, nil
+		}
+	}
+	return nil, notFound("execution", id)
+}
+
+// latestExecution returns the most recently created execution of a task.
+func (s *Server) latestExecution(task string) *execution {
+	var latest *execution
+	for _, x := range s.executions {
+		if x.Task == task {
+			latest = x
+		}
+	}
+	return latest
+}
+
+// cancel stops an execution if it hasn't already finished.
+func (x *execution) cancel() {
+	if x.State.Finalized != nil {
+		return
+	}
+	t := now()
+	x.State.Canceled = &t
+	x.State.Finalized = &t
+}
+
+// SetMetrics sets the metrics reported in an execution's results.
+func (s *Server) SetMetrics(execution string, metrics map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	x, err := s.findExecution(execution)
+	if err != nil {
+		return err
+	}
+	x.metrics = metrics
+	return nil
+}
+
+func (s *Server) getExecution(r *http.Request, args []string) (interface{}, error) {
+	x, err := s.findExecution(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return x.Execution, nil
+}
+
+func (s *Server) getLogs(r *http.Request, args []string) (interface{}, error) {
+	x, err := s.findExecution(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		if since, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid value %q for since", v)
+		}
+	}
+	return rawResponse{contentType: "text/plain", body: filterLogs(x.logs, since)}, nil
+}
+
+// filterLogs removes lines written before since. Lines without a timestamp
+// are kept or removed along with the line before them.
+func filterLogs(logs []byte, since time.Time) []byte {
+	if since.IsZero() {
+		return logs
+	}
+
+	var out bytes.Buffer
+	keep := false
+	for _, line := range bytes.SplitAfter(logs, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		field := string(line)
+		if i := strings.IndexAny(field, " \n"); i >= 0 {
+			field = field[:i]
+		}
+		if t, err := time.Parse(time.RFC3339Nano, field); err == nil {
+			keep = !t.Before(since)
+		}
+		if keep {
+			out.Write(line)
+		}
+	}
+	return out.Bytes()
+}
+
+func (s *Server) putLogs(r *http.Request, args []string) (interface{}, error) {
+	x, err := s.findExecution(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "failed to read logs: %v", err)
+	}
+	if len(x.logs) != 0 && x.logs[len(x.logs)-1] != '\n' {
+		x.logs = append(x.logs, '\n')
+	}
+	x.logs = append(x.logs, logs...)
+	return nil, nil
+}
+
+func (s *Server) getResults(r *http.Request, args []string) (interface{}, error) {
+	x, err := s.findExecution(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := map[string]interface{}{}
+	for k, v := range x.metrics {
+		metrics[k] = v
+	}
+	return api.ExecutionResults{Metrics: metrics}, nil
+}
+
+func (s *Server) postStatus(r *http.Request, args []string) (interface{}, error) {
+	x, err := s.findExecution(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var update api.ExecStatusUpdate
+	if err := decodeBody(r, &update); err != nil {
+		return nil, err
+	}
+	applyStatus(&x.State, update)
+	if update.Limits != nil {
+		x.Limits = *update.Limits
+	}
+	return nil, nil
+}
+
+// applyStatus updates state to reflect a status update. Timestamps which are
+// already set are left unchanged.
+func applyStatus(state *api.ExecutionState, update api.ExecStatusUpdate) {
+	t := now()
+	set := func(field **time.Time, ok bool) {
+		if ok && *field == nil {
+			*field = &t
+		}
+	}
+
+	set(&state.Scheduled, update.Scheduled)
+	set(&state.Started, update.Started)
+	if update.ExitCode != nil {
+		code := *update.ExitCode
+		state.ExitCode = &code
+		set(&state.Exited, true)
+	}
+	set(&state.Failed, update.Failed)
+	set(&state.Canceled, update.Canceled)
+	set(&state.Finalized, update.Finalized)
+	if update.Message != nil {
+		state.Message = *update.Message
+	}
+}
+
+func (s *Server) stopExecution(r *http.Request, args []string) (interface{}, error) {
+	x, err := s.findExecution(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	requeue := false
+	if v := r.URL.Query().Get("requeue"); v != "" {
+		if requeue, err = strconv.ParseBool(v); err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid value %q for requeue", v)
+		}
+	}
+
+	if x.State.Finalized != nil {
+		return nil, errorf(http.StatusConflict, "execution %q has already finished", x.ID)
+	}
+	x.cancel()
+
+	if requeue {
+		e, err := s.findExperiment(x.Experiment)
+		if err != nil {
+			return nil, err
+		}
+		t, err := s.findTask(x.Task)
+		if err != nil {
+			return nil, err
+		}
+		s.startExecution(e, t, x.Spec)
+	}
+	return nil, nil
+}
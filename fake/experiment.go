@@ -0,0 +1,315 @@
+package fake
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/beaker/client/api"
+)
+
+type experiment struct {
+	api.Experiment // Executions are populated on read.
+	spec           api.ExperimentSpecV2
+}
+
+func (s *Server) findExperiment(ref string) (*experiment, error) {
+	name := s.itemName(ref)
+	for _, e := range s.experiments {
+		if e.ID == ref || (e.FullName != "" && e.FullName == name) {
+			return e, nil
+		}
+	}
+	return nil, notFound("experiment", ref)
+}
+
+func (s *Server) findTask(id string) (*api.Task, error) {
+	for _, t := range s.tasks {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, notFound("task", id)
+}
+
+// viewExperiment returns an experiment as the service presents it.
+func (s *Server) viewExperiment(e *experiment) api.Experiment {
+	view := e.Experiment
+	for _, x := range s.executions {
+		if x.Experiment == e.ID {
+			execution := x.Execution
+			view.Executions = append(view.Executions, &execution)
+		}
+	}
+	return view
+}
+
+// viewTask returns a task as the service presents it.
+func (s *Server) viewTask(t *api.Task) api.Task {
+	view := *t
+	view.Executions = nil
+	for _, x := range s.executions {
+		if x.Task == t.ID {
+			view.Executions = append(view.Executions, x.Execution)
+		}
+	}
+	return view
+}
+
+func (s *Server) createExperiment(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.targetWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "failed to read spec: %v", err)
+	}
+	spec, err := api.ParseExperimentSpec(body)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "%v", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, errorf(http.StatusBadRequest, "%v", err)
+	}
+
+	name := r.URL.Query().Get("name")
+	if _, err := s.findExperiment(fullName(s.User.Name, name)); name != "" && err == nil {
+		return nil, conflict("experiment", fullName(s.User.Name, name))
+	}
+
+	e := &experiment{
+		Experiment: api.Experiment{
+			ID:          s.newID(),
+			Name:        name,
+			FullName:    fullName(s.User.Name, name),
+			Owner:       s.User.Identity,
+			Author:      s.User.Identity,
+			Workspace:   w.ref(),
+			Description: spec.Description,
+			Created:     now(),
+		},
+		spec: *spec,
+	}
+	s.experiments = append(s.experiments, e)
+
+	for _, taskSpec := range spec.Tasks {
+		t := &api.Task{
+			ID:           s.newID(),
+			ExperimentID: e.ID,
+			Name:         taskSpec.Name,
+			Owner:        s.User.Identity,
+			Author:       s.User.Identity,
+			Created:      e.Created,
+			Schedulable:  true,
+		}
+		s.tasks = append(s.tasks, t)
+		s.startExecution(e, t, taskSpec)
+	}
+	return s.viewExperiment(e), nil
+}
+
+// startExecution creates a pending execution of a task, along with a dataset
+// to hold its results.
+func (s *Server) startExecution(e *experiment, t *api.Task, spec api.TaskSpecV2) *execution {
+	x := &execution{Execution: api.Execution{
+		ID:         s.newID(),
+		Task:       t.ID,
+		Experiment: e.ID,
+		Workspace:  e.Workspace.ID,
+		Author:     s.User.Identity,
+		Spec:       spec,
+		State:      api.ExecutionState{Created: now()},
+		Priority:   spec.Context.Priority,
+	}}
+	if x.Priority == "" {
+		x.Priority = api.NormalPriority
+	}
+
+	result := &api.Dataset{
+		ID:              s.newID(),
+		Owner:           s.User.Identity,
+		Author:          s.User.Identity,
+		Workspace:       e.Workspace,
+		Created:         x.State.Created,
+		SourceExecution: x.ID,
+	}
+	s.datasets = append(s.datasets, result)
+	x.Result.Beaker = result.ID
+
+	s.executions = append(s.executions, x)
+	return x
+}
+
+func (s *Server) getExperiment(r *http.Request, args []string) (interface{}, error) {
+	e, err := s.findExperiment(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.viewExperiment(e), nil
+}
+
+func (s *Server) patchExperiment(r *http.Request, args []string) (interface{}, error) {
+	e, err := s.findExperiment(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var patch api.ExperimentPatchSpec
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil {
+		name := fullName(e.Author.Name, *patch.Name)
+		if other, err := s.findExperiment(name); name != "" && err == nil && other != e {
+			return nil, conflict("experiment", name)
+		}
+		e.Name, e.FullName = *patch.Name, name
+	}
+	if patch.Description != nil {
+		e.Description = *patch.Description
+	}
+	return s.viewExperiment(e), nil
+}
+
+func (s *Server) deleteExperiment(r *http.Request, args []string) (interface{}, error) {
+	e, err := s.findExperiment(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	experiments := s.experiments[:0]
+	for _, other := range s.experiments {
+		if other != e {
+			experiments = append(experiments, other)
+		}
+	}
+	s.experiments = experiments
+
+	tasks := s.tasks[:0]
+	for _, t := range s.tasks {
+		if t.ExperimentID != e.ID {
+			tasks = append(tasks, t)
+		}
+	}
+	s.tasks = tasks
+
+	executions := s.executions[:0]
+	for _, x := range s.executions {
+		if x.Experiment != e.ID {
+			executions = append(executions, x)
+		}
+	}
+	s.executions = executions
+
+	for _, g := range s.groups {
+		for i, id := range g.experiments {
+			if id == e.ID {
+				g.experiments = append(g.experiments[:i], g.experiments[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) listExperimentGroups(r *http.Request, args []string) (interface{}, error) {
+	e, err := s.findExperiment(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []string{}
+	for _, g := range s.groups {
+		if g.contains(e.ID) {
+			groups = append(groups, g.ID)
+		}
+	}
+	return groups, nil
+}
+
+func (s *Server) getExperimentSpec(r *http.Request, args []string) (interface{}, error) {
+	e, err := s.findExperiment(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	switch version := r.URL.Query().Get("version"); version {
+	case "", "v2", "v2-alpha":
+	default:
+		return nil, errorf(http.StatusBadRequest, "spec version %q is not supported", version)
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		b, err := json.Marshal(e.spec)
+		if err != nil {
+			return nil, err
+		}
+		return rawResponse{contentType: "application/json", body: b}, nil
+	}
+
+	b, err := yaml.Marshal(e.spec)
+	if err != nil {
+		return nil, err
+	}
+	return rawResponse{contentType: "application/x-yaml", body: b}, nil
+}
+
+func (s *Server) resumeExperiment(r *http.Request, args []string) (interface{}, error) {
+	e, err := s.findExperiment(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range s.tasks {
+		if t.ExperimentID != e.ID {
+			continue
+		}
+		if latest := s.latestExecution(t.ID); latest != nil && latest.State.Canceled != nil {
+			s.startExecution(e, t, latest.Spec)
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) stopExperiment(r *http.Request, args []string) (interface{}, error) {
+	e, err := s.findExperiment(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, x := range s.executions {
+		if x.Experiment == e.ID {
+			x.cancel()
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) listExperimentTasks(r *http.Request, args []string) (interface{}, error) {
+	e, err := s.findExperiment(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []api.Task{}
+	for _, t := range s.tasks {
+		if t.ExperimentID == e.ID {
+			tasks = append(tasks, s.viewTask(t))
+		}
+	}
+	return tasks, nil
+}
+
+func (s *Server) getTask(r *http.Request, args []string) (interface{}, error) {
+	t, err := s.findTask(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.viewTask(t), nil
+}
@@ -0,0 +1,160 @@
+package fake
+
+import (
+	"net/http"
+
+	"github.com/beaker/client/api"
+)
+
+type group struct {
+	api.Group
+	experiments []string // IDs
+}
+
+func (s *Server) findGroup(ref string) (int, error) {
+	name := s.itemName(ref)
+	for i, g := range s.groups {
+		if g.ID == ref || (g.FullName != "" && g.FullName == name) {
+			return i, nil
+		}
+	}
+	return 0, notFound("group", ref)
+}
+
+// resolveExperiments maps experiment references to IDs.
+func (s *Server) resolveExperiments(refs []string) ([]string, error) {
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		e, err := s.findExperiment(ref)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, e.ID)
+	}
+	return ids, nil
+}
+
+func (s *Server) createGroup(r *http.Request, args []string) (interface{}, error) {
+	var spec api.GroupSpec
+	if err := decodeBody(r, &spec); err != nil {
+		return nil, err
+	}
+	w, err := s.targetWorkspace(spec.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fullName(s.User.Name, spec.Name)
+	if _, err := s.findGroup(name); spec.Name != "" && err == nil {
+		return nil, conflict("group", name)
+	}
+
+	experiments, err := s.resolveExperiments(spec.Experiments)
+	if err != nil {
+		return nil, err
+	}
+
+	t := now()
+	g := &group{
+		Group: api.Group{
+			ID:          s.newID(),
+			Name:        spec.Name,
+			FullName:    name,
+			Owner:       s.User.Identity,
+			Author:      s.User.Identity,
+			Workspace:   w.ref(),
+			Description: spec.Description,
+			Created:     t,
+			Modified:    t,
+		},
+	}
+	g.addExperiments(experiments)
+	s.groups = append(s.groups, g)
+	return api.CreateGroupResponse{ID: g.ID}, nil
+}
+
+func (g *group) addExperiments(ids []string) {
+	for _, id := range ids {
+		if !g.contains(id) {
+			g.experiments = append(g.experiments, id)
+		}
+	}
+}
+
+func (g *group) contains(id string) bool {
+	for _, e := range g.experiments {
+		if e == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getGroup(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findGroup(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.groups[i].Group, nil
+}
+
+func (s *Server) patchGroup(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findGroup(args[0])
+	if err != nil {
+		return nil, err
+	}
+	g := s.groups[i]
+
+	var patch api.GroupPatchSpec
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+
+	add, err := s.resolveExperiments(patch.AddExperiments)
+	if err != nil {
+		return nil, err
+	}
+	remove, err := s.resolveExperiments(patch.RemoveExperiments)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil {
+		name := fullName(g.Author.Name, *patch.Name)
+		if j, err := s.findGroup(name); name != "" && err == nil && j != i {
+			return nil, conflict("group", name)
+		}
+		g.Name, g.FullName = *patch.Name, name
+	}
+	if patch.Description != nil {
+		g.Description = *patch.Description
+	}
+	g.addExperiments(add)
+	for _, id := range remove {
+		for j, e := range g.experiments {
+			if e == id {
+				g.experiments = append(g.experiments[:j], g.experiments[j+1:]...)
+				break
+			}
+		}
+	}
+	g.Modified = now()
+	return g.Group, nil
+}
+
+func (s *Server) deleteGroup(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findGroup(args[0])
+	if err != nil {
+		return nil, err
+	}
+	s.groups = append(s.groups[:i], s.groups[i+1:]...)
+	return nil, nil
+}
+
+func (s *Server) listGroupExperiments(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findGroup(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, s.groups[i].experiments...), nil
+}
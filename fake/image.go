@@ -0,0 +1,124 @@
+package fake
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/beaker/client/api"
+)
+
+// registryHost is the address given for pushing and pulling images. No
+// registry is actually served.
+const registryHost = "registry.beaker.test"
+
+func (s *Server) findImage(ref string) (int, error) {
+	name := s.itemName(ref)
+	for i, image := range s.images {
+		if image.ID == ref || (image.FullName != "" && image.FullName == name) {
+			return i, nil
+		}
+	}
+	return 0, notFound("image", ref)
+}
+
+func (s *Server) createImage(r *http.Request, args []string) (interface{}, error) {
+	var spec api.ImageSpec
+	if err := decodeBody(r, &spec); err != nil {
+		return nil, err
+	}
+	w, err := s.targetWorkspace(spec.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	name := r.URL.Query().Get("name")
+	if _, err := s.findImage(fullName(s.User.Name, name)); name != "" && err == nil {
+		return nil, conflict("image", fullName(s.User.Name, name))
+	}
+
+	image := &api.Image{
+		ID:          s.newID(),
+		Name:        name,
+		FullName:    fullName(s.User.Name, name),
+		Owner:       s.User.Identity,
+		Author:      s.User.Identity,
+		Workspace:   w.ref(),
+		Created:     now(),
+		OriginalTag: spec.ImageTag,
+		Description: spec.Description,
+	}
+	s.images = append(s.images, image)
+	return *image, nil
+}
+
+func (s *Server) getImage(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findImage(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return *s.images[i], nil
+}
+
+func (s *Server) patchImage(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findImage(args[0])
+	if err != nil {
+		return nil, err
+	}
+	image := s.images[i]
+
+	var patch api.ImagePatchSpec
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil {
+		name := fullName(image.Author.Name, *patch.Name)
+		if j, err := s.findImage(name); name != "" && err == nil && j != i {
+			return nil, conflict("image", name)
+		}
+		image.Name, image.FullName = *patch.Name, name
+	}
+	if patch.Description != nil {
+		image.Description = *patch.Description
+	}
+	if patch.Commit && image.Committed.IsZero() {
+		image.Committed = now()
+	}
+	return *image, nil
+}
+
+func (s *Server) deleteImage(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findImage(args[0])
+	if err != nil {
+		return nil, err
+	}
+	s.images = append(s.images[:i], s.images[i+1:]...)
+	return nil, nil
+}
+
+func (s *Server) getImageRepository(r *http.Request, args []string) (interface{}, error) {
+	i, err := s.findImage(args[0])
+	if err != nil {
+		return nil, err
+	}
+	image := s.images[i]
+
+	if v := r.URL.Query().Get("upload"); v != "" {
+		upload, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid value %q for upload", v)
+		}
+		if upload && !image.Committed.IsZero() {
+			return nil, errorf(http.StatusForbidden, "image %q is committed and can't be modified", image.ID)
+		}
+	}
+
+	return api.ImageRepository{
+		ImageTag: registryHost + "/" + image.ID,
+		Auth: api.RegistryAuth{
+			ServerAddress: registryHost,
+			User:          s.User.Name,
+			Password:      s.Token,
+		},
+	}, nil
+}
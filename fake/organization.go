@@ -0,0 +1,207 @@
+package fake
+
+import (
+	"net/http"
+
+	"github.com/beaker/client/api"
+)
+
+type organization struct {
+	api.Organization
+	members map[string]string // User ID to role.
+}
+
+func (s *Server) findUser(ref string) (*api.UserDetail, error) {
+	for _, u := range s.users {
+		if u.ID == ref || u.Name == ref {
+			return u, nil
+		}
+	}
+	return nil, notFound("user", ref)
+}
+
+func (s *Server) findOrg(ref string) (*organization, error) {
+	for _, o := range s.orgs {
+		if o.ID == ref || o.Name == ref {
+			return o, nil
+		}
+	}
+	return nil, notFound("organization", ref)
+}
+
+// findAccount returns the name of the user or organization matching ref.
+func (s *Server) findAccount(ref string) (string, error) {
+	if u, err := s.findUser(ref); err == nil {
+		return u.Name, nil
+	}
+	if o, err := s.findOrg(ref); err == nil {
+		return o.Name, nil
+	}
+	return "", notFound("account", ref)
+}
+
+func (s *Server) createToken(r *http.Request, args []string) (interface{}, error) {
+	return s.Token, nil
+}
+
+func (s *Server) whoAmI(r *http.Request, args []string) (interface{}, error) {
+	return s.User, nil
+}
+
+func (s *Server) getUser(r *http.Request, args []string) (interface{}, error) {
+	return s.findUser(args[0])
+}
+
+func (s *Server) listUsers(r *http.Request, args []string) (interface{}, error) {
+	start, end, next, err := paginate(r, len(s.users))
+	if err != nil {
+		return nil, err
+	}
+
+	page := api.UserPage{Data: []api.UserDetail{}, NextCursor: next}
+	for _, u := range s.users[start:end] {
+		page.Data = append(page.Data, *u)
+	}
+	return page, nil
+}
+
+func (s *Server) listMyOrgs(r *http.Request, args []string) (interface{}, error) {
+	page := api.OrganizationPage{Data: []api.Organization{}}
+	for _, o := range s.orgs {
+		if _, ok := o.members[s.User.ID]; ok {
+			page.Data = append(page.Data, o.Organization)
+		}
+	}
+	return page, nil
+}
+
+func (s *Server) listOrgs(r *http.Request, args []string) (interface{}, error) {
+	start, end, next, err := paginate(r, len(s.orgs))
+	if err != nil {
+		return nil, err
+	}
+
+	page := api.OrganizationPage{Data: []api.Organization{}, NextCursor: next}
+	for _, o := range s.orgs[start:end] {
+		page.Data = append(page.Data, o.Organization)
+	}
+	return page, nil
+}
+
+func (s *Server) createOrg(r *http.Request, args []string) (interface{}, error) {
+	var spec api.OrganizationSpec
+	if err := decodeBody(r, &spec); err != nil {
+		return nil, err
+	}
+	if spec.Name == "" {
+		return nil, errorf(http.StatusBadRequest, "organization name is required")
+	}
+	if _, err := s.findAccount(spec.Name); err == nil {
+		return nil, conflict("account", spec.Name)
+	}
+
+	owner, err := s.findUser(spec.Owner)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "owner %q not found", spec.Owner)
+	}
+
+	org := &organization{
+		Organization: api.Organization{
+			Identity:    api.Identity{ID: s.newID(), Name: spec.Name, DisplayName: spec.DisplayName},
+			Created:     now(),
+			Description: spec.Description,
+		},
+		members: map[string]string{owner.ID: "admin"},
+	}
+	s.orgs = append(s.orgs, org)
+	return org.Organization, nil
+}
+
+func (s *Server) getOrg(r *http.Request, args []string) (interface{}, error) {
+	org, err := s.findOrg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return org.Organization, nil
+}
+
+func (s *Server) listMembers(r *http.Request, args []string) (interface{}, error) {
+	org, err := s.findOrg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// Preserve the order in which users were created.
+	var members []api.UserDetail
+	for _, u := range s.users {
+		if _, ok := org.members[u.ID]; ok {
+			members = append(members, *u)
+		}
+	}
+
+	start, end, next, err := paginate(r, len(members))
+	if err != nil {
+		return nil, err
+	}
+	return api.UserPage{Data: append([]api.UserDetail{}, members[start:end]...), NextCursor: next}, nil
+}
+
+func (s *Server) getMember(r *http.Request, args []string) (interface{}, error) {
+	org, err := s.findOrg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	role, ok := org.members[user.ID]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "%q is not a member of %q", user.Name, org.Name)
+	}
+	return api.OrgMembership{Role: role, Organization: org.Organization, User: *user}, nil
+}
+
+func (s *Server) setMember(r *http.Request, args []string) (interface{}, error) {
+	org, err := s.findOrg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := org.members[user.ID]; !ok {
+		org.members[user.ID] = "member"
+	}
+	return nil, nil
+}
+
+func (s *Server) removeMember(r *http.Request, args []string) (interface{}, error) {
+	org, err := s.findOrg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := org.members[user.ID]; !ok {
+		return nil, errorf(http.StatusNotFound, "%q is not a member of %q", user.Name, org.Name)
+	}
+	delete(org.members, user.ID)
+	return nil, nil
+}
+
+// AddUser creates a user account which belongs to no organizations.
+func (s *Server) AddUser(name string) api.UserDetail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := &api.UserDetail{Identity: api.Identity{ID: s.newID(), Name: name}}
+	s.users = append(s.users, user)
+	return *user
+}
@@ -0,0 +1,361 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/beaker/client/api"
+)
+
+// The report endpoints follow the client's experimental report methods and
+// haven't been verified against the service.
+
+// SetNodeUsage sets the node usage from which node usage reports are computed.
+// Each series should be labeled with every dimension and split into intervals
+// of the length which will be requested. The same values are reported for
+// every metric.
+func (s *Server) SetNodeUsage(series []api.NodeUsageSeries) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodeUsage = append([]api.NodeUsageSeries(nil), series...)
+}
+
+// SetTaskUsage sets the task usage from which task usage reports are computed.
+// Each series should be labeled with every dimension and split into intervals
+// of the length which will be requested. The same values are reported for
+// every metric.
+func (s *Server) SetTaskUsage(series []api.TaskUsageSeries) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.taskUsage = append([]api.TaskUsageSeries(nil), series...)
+}
+
+// usageQuery holds the parameters of a usage report request.
+type usageQuery struct {
+	start, end time.Time
+	groupBy    map[string]bool
+
+	// At most one of these scopes is set.
+	cluster   *api.Cluster
+	workspace *workspace
+	org       *organization
+}
+
+var (
+	nodeMetrics = []string{
+		string(api.NodeMetricHours),
+		string(api.NodeMetricCPUHours),
+		string(api.NodeMetricGPUHours),
+	}
+	nodeDimensions = []string{
+		string(api.NodeGroupByNode),
+		string(api.NodeGroupByCluster),
+		string(api.NodeGroupByPreemptible),
+		string(api.NodeGroupByAutoscale),
+		string(api.NodeGroupByGPUCount),
+		string(api.NodeGroupByGPUType),
+	}
+	taskMetrics = []string{
+		string(api.TaskMetricHours),
+		string(api.TaskMetricCPUHours),
+		string(api.TaskMetricGPUHours),
+	}
+	taskDimensions = []string{
+		string(api.TaskGroupByTask),
+		string(api.TaskGroupByExperiment),
+		string(api.TaskGroupByWorkspace),
+		string(api.TaskGroupByNode),
+		string(api.TaskGroupByCluster),
+		string(api.TaskGroupByAuthor),
+		string(api.TaskGroupByOwner),
+		string(api.TaskGroupByTeam),
+		string(api.TaskGroupByAutoscale),
+		string(api.TaskGroupByGPUCount),
+		string(api.TaskGroupByGPUType),
+	}
+	reportIntervals = []string{
+		string(api.IntervalHour),
+		string(api.IntervalDay),
+		string(api.IntervalWeek),
+		string(api.IntervalMonth),
+		string(api.IntervalQuarter),
+		string(api.IntervalYear),
+	}
+)
+
+// parseUsageQuery validates a usage report request. Every parameter except
+// the scope and group-by is required.
+func (s *Server) parseUsageQuery(r *http.Request, metrics, dimensions, scopes []string) (*usageQuery, error) {
+	query := r.URL.Query()
+	q := &usageQuery{groupBy: map[string]bool{}}
+
+	var err error
+	if q.start, err = time.Parse(time.RFC3339, query.Get("start")); err != nil {
+		return nil, errorf(http.StatusBadRequest, "invalid start: %v", err)
+	}
+	if q.end, err = time.Parse(time.RFC3339, query.Get("end")); err != nil {
+		return nil, errorf(http.StatusBadRequest, "invalid end: %v", err)
+	}
+	if !q.end.After(q.start) {
+		return nil, errorf(http.StatusBadRequest, "end must be after start")
+	}
+	if interval := query.Get("interval"); !contains(reportIntervals, interval) {
+		return nil, errorf(http.StatusBadRequest, "invalid interval %q", interval)
+	}
+	if metric := query.Get("metric"); !contains(metrics, metric) {
+		return nil, errorf(http.StatusBadRequest, "invalid metric %q", metric)
+	}
+	for _, g := range query["groupBy"] {
+		if !contains(dimensions, g) {
+			return nil, errorf(http.StatusBadRequest, "invalid groupBy %q", g)
+		}
+		q.groupBy[g] = true
+	}
+
+	var scope []string
+	for key := range query {
+		switch key {
+		case "start", "end", "interval", "metric", "groupBy":
+		default:
+			if !contains(scopes, key) {
+				return nil, errorf(http.StatusBadRequest, "unknown parameter %q", key)
+			}
+			scope = append(scope, key)
+		}
+	}
+	if len(scope) > 1 {
+		return nil, errorf(http.StatusBadRequest, "only one of %s may be given", strings.Join(scopes, ", "))
+	}
+	if len(scope) == 0 {
+		return q, nil
+	}
+
+	ref := query.Get(scope[0])
+	switch scope[0] {
+	case "cluster":
+		q.cluster, err = s.findCluster(ref)
+	case "workspace":
+		q.workspace, err = s.findWorkspace(ref)
+	case "org":
+		q.org, err = s.findOrg(ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesCluster reports whether usage on a cluster is within the query's scope.
+func (q *usageQuery) matchesCluster(cluster string) bool {
+	switch {
+	case q.cluster != nil:
+		return cluster == q.cluster.ID || cluster == q.cluster.FullName
+	case q.org != nil:
+		return strings.HasPrefix(cluster, q.org.Name+"/")
+	}
+	return true
+}
+
+// matchesTask reports whether a task's usage is within the query's scope.
+// Tasks belong to an organization through their workspace.
+func (s *Server) matchesTask(q *usageQuery, series *api.TaskUsageSeries) bool {
+	if q.cluster != nil {
+		return q.matchesCluster(series.Cluster)
+	}
+	if q.workspace == nil && q.org == nil {
+		return true
+	}
+	w, err := s.findWorkspace(series.Workspace)
+	if err != nil {
+		return false
+	}
+	if q.workspace != nil {
+		return w == q.workspace
+	}
+	return w.org == q.org.ID
+}
+
+// usageGroups sums the intervals of series which share the same labels.
+type usageGroups struct {
+	start, end time.Time
+	keys       map[string]int
+	totals     api.UsageInterval
+	series     []api.UsageInterval // Totals of each group.
+	intervals  [][]api.UsageInterval
+}
+
+func newUsageGroups(q *usageQuery) *usageGroups {
+	return &usageGroups{
+		start:  q.start,
+		end:    q.end,
+		keys:   map[string]int{},
+		totals: api.UsageInterval{Start: q.start, End: q.end},
+	}
+}
+
+// add sums a series' intervals within the report's period into the group
+// identified by labels. It reports whether the group is new. Groups are
+// indexed in the order they're added.
+func (g *usageGroups) add(labels interface{}, intervals []api.UsageInterval) (bool, error) {
+	encoded, err := json.Marshal(labels)
+	if err != nil {
+		return false, err
+	}
+	key := string(encoded)
+
+	i, ok := g.keys[key]
+	if !ok {
+		i = len(g.series)
+		g.keys[key] = i
+		g.series = append(g.series, api.UsageInterval{Start: g.start, End: g.end})
+		g.intervals = append(g.intervals, nil)
+	}
+
+	for _, interval := range intervals {
+		if interval.Start.Before(g.start) || !interval.Start.Before(g.end) {
+			continue
+		}
+		g.series[i].Value += interval.Value
+		g.totals.Value += interval.Value
+
+		merged := false
+		for j := range g.intervals[i] {
+			if g.intervals[i][j].Start.Equal(interval.Start) {
+				g.intervals[i][j].Value += interval.Value
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			g.intervals[i] = append(g.intervals[i], interval)
+		}
+	}
+	return !ok, nil
+}
+
+func (s *Server) getNodeUsageReport(r *http.Request, args []string) (interface{}, error) {
+	q, err := s.parseUsageQuery(r, nodeMetrics, nodeDimensions, []string{"cluster", "org"})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := newUsageGroups(q)
+	var report api.NodeUsageReport
+	for _, series := range s.nodeUsage {
+		if !q.matchesCluster(series.Cluster) {
+			continue
+		}
+
+		var labels api.NodeUsageSeries
+		if q.groupBy[string(api.NodeGroupByNode)] {
+			labels.Node = series.Node
+		}
+		if q.groupBy[string(api.NodeGroupByCluster)] {
+			labels.Cluster = series.Cluster
+		}
+		if q.groupBy[string(api.NodeGroupByPreemptible)] {
+			labels.Preemptible = series.Preemptible
+		}
+		if q.groupBy[string(api.NodeGroupByAutoscale)] {
+			labels.Autoscale = series.Autoscale
+		}
+		if q.groupBy[string(api.NodeGroupByGPUCount)] {
+			labels.GPUCount = series.GPUCount
+		}
+		if q.groupBy[string(api.NodeGroupByGPUType)] {
+			labels.GPUType = series.GPUType
+		}
+
+		added, err := groups.add(labels, series.Intervals)
+		if err != nil {
+			return nil, err
+		}
+		if added {
+			report.Series = append(report.Series, labels)
+		}
+	}
+
+	for i := range report.Series {
+		report.Series[i].Totals = groups.series[i]
+		report.Series[i].Intervals = groups.intervals[i]
+	}
+	report.Totals = groups.totals
+	return report, nil
+}
+
+func (s *Server) getTaskUsageReport(r *http.Request, args []string) (interface{}, error) {
+	q, err := s.parseUsageQuery(r, taskMetrics, taskDimensions, []string{"cluster", "workspace", "org"})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := newUsageGroups(q)
+	var report api.TaskUsageReport
+	for i := range s.taskUsage {
+		series := &s.taskUsage[i]
+		if !s.matchesTask(q, series) {
+			continue
+		}
+
+		var labels api.TaskUsageSeries
+		if q.groupBy[string(api.TaskGroupByTask)] {
+			labels.Task = series.Task
+		}
+		if q.groupBy[string(api.TaskGroupByExperiment)] {
+			labels.Experiment = series.Experiment
+		}
+		if q.groupBy[string(api.TaskGroupByWorkspace)] {
+			labels.Workspace = series.Workspace
+		}
+		if q.groupBy[string(api.TaskGroupByNode)] {
+			labels.Node = series.Node
+		}
+		if q.groupBy[string(api.TaskGroupByCluster)] {
+			labels.Cluster = series.Cluster
+		}
+		if q.groupBy[string(api.TaskGroupByAuthor)] {
+			labels.Author = series.Author
+		}
+		if q.groupBy[string(api.TaskGroupByOwner)] {
+			labels.Owner = series.Owner
+		}
+		if q.groupBy[string(api.TaskGroupByTeam)] {
+			labels.Team = series.Team
+		}
+		if q.groupBy[string(api.TaskGroupByAutoscale)] {
+			labels.Autoscale = series.Autoscale
+		}
+		if q.groupBy[string(api.TaskGroupByGPUCount)] {
+			labels.GPUCount = series.GPUCount
+		}
+		if q.groupBy[string(api.TaskGroupByGPUType)] {
+			labels.GPUType = series.GPUType
+		}
+
+		added, err := groups.add(labels, series.Intervals)
+		if err != nil {
+			return nil, err
+		}
+		if added {
+			report.Series = append(report.Series, labels)
+		}
+	}
+
+	for i := range report.Series {
+		report.Series[i].Totals = groups.series[i]
+		report.Series[i].Intervals = groups.intervals[i]
+	}
+	report.Totals = groups.totals
+	return report, nil
+}
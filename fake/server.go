@@ -0,0 +1,437 @@
+// Package fake provides an in-memory Beaker service for hermetic tests.
+//
+// The fake implements the /api/v3 routes called by the client package. State
+// is held in memory and discarded when the server is closed. Dataset file
+// storage, image registries, and search are not emulated. Usage reports are
+// computed from usage set with SetNodeUsage and SetTaskUsage.
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/beaker/client/api"
+)
+
+const (
+	// DefaultPageSize is the number of items returned per page when a request
+	// doesn't specify a limit.
+	DefaultPageSize = 100
+
+	// MaxPageSize is the largest number of items returned in a single page.
+	MaxPageSize = 1000
+)
+
+// Server is a fake Beaker service. All requests must be authenticated with
+// Token, and are attributed to User.
+type Server struct {
+	errorCount int64 // Accessed atomically; first for alignment.
+
+	// URL of the server, suitable for passing to client.NewClient.
+	URL string
+
+	// Token authenticates requests as User.
+	Token string
+
+	// User is the account which issues all requests. It is a member of Org.
+	User api.UserDetail
+
+	// Org is the default organization, in which workspaces are created
+	// unless another is given.
+	Org api.Organization
+
+	server *httptest.Server
+	routes []route
+
+	mu          sync.Mutex
+	nextID      int
+	users       []*api.UserDetail
+	orgs        []*organization
+	workspaces  []*workspace
+	datasets    []*api.Dataset
+	experiments []*experiment
+	tasks       []*api.Task
+	executions  []*execution
+	groups      []*group
+	images      []*api.Image
+	clusters    []*api.Cluster
+	nodes       []*node
+	sessions    []*api.Session
+	nodeUsage   []api.NodeUsageSeries
+	taskUsage   []api.TaskUsageSeries
+}
+
+// NewServer starts a fake Beaker service. The caller must call Close when
+// finished with it.
+func NewServer() *Server {
+	s := &Server{Token: "fake-token"}
+	s.routes = s.newRoutes()
+
+	s.User = api.UserDetail{
+		Identity: api.Identity{ID: s.newID(), Name: "user", DisplayName: "Test User"},
+		Role:     "admin",
+	}
+	s.users = []*api.UserDetail{&s.User}
+
+	s.Org = api.Organization{
+		Identity: api.Identity{ID: s.newID(), Name: "org", DisplayName: "Test Org"},
+		Created:  now(),
+	}
+	s.orgs = []*organization{{
+		Organization: s.Org,
+		members:      map[string]string{s.User.ID: "admin"},
+	}}
+
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server and blocks until all requests complete.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// newID returns a new unique ID in the style of the service's IDs. The caller
+// must hold s.mu or have exclusive access to the server.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("01F%023d", s.nextID)
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// handler serves a single route. Wildcard path segments are passed as args.
+// The returned value is encoded as JSON unless it's a rawResponse.
+type handler func(r *http.Request, args []string) (interface{}, error)
+
+// rawResponse is a non-JSON response body.
+type rawResponse struct {
+	contentType string
+	body        []byte
+}
+
+type route struct {
+	method  string
+	pattern []string // A "*" matches any single segment.
+	handle  handler
+}
+
+func (s *Server) newRoutes() []route {
+	r := func(method, pattern string, handle handler) route {
+		return route{method: method, pattern: strings.Split(pattern, "/"), handle: handle}
+	}
+	return []route{
+		r(http.MethodPost, "auth/tokens", s.createToken),
+		r(http.MethodGet, "user", s.whoAmI),
+		r(http.MethodGet, "user/orgs", s.listMyOrgs),
+		r(http.MethodGet, "users/*", s.getUser),
+		r(http.MethodGet, "admin/users", s.listUsers),
+		r(http.MethodGet, "admin/orgs", s.listOrgs),
+		r(http.MethodPost, "admin/orgs", s.createOrg),
+		r(http.MethodGet, "orgs/*", s.getOrg),
+		r(http.MethodGet, "orgs/*/members", s.listMembers),
+		r(http.MethodGet, "orgs/*/members/*", s.getMember),
+		r(http.MethodPut, "orgs/*/members/*", s.setMember),
+		r(http.MethodDelete, "orgs/*/members/*", s.removeMember),
+
+		r(http.MethodPost, "workspaces", s.createWorkspace),
+		r(http.MethodGet, "workspaces", s.listWorkspaces),
+		r(http.MethodGet, "workspaces/*", s.getWorkspace),
+		r(http.MethodPatch, "workspaces/*", s.patchWorkspace),
+		r(http.MethodPost, "workspaces/*/transfer", s.transferToWorkspace),
+		r(http.MethodGet, "workspaces/*/auth", s.getWorkspacePermissions),
+		r(http.MethodPatch, "workspaces/*/auth", s.patchWorkspacePermissions),
+		r(http.MethodGet, "workspaces/*/datasets", s.listWorkspaceDatasets),
+		r(http.MethodGet, "workspaces/*/experiments", s.listWorkspaceExperiments),
+		r(http.MethodPost, "workspaces/*/experiments", s.createExperiment),
+		r(http.MethodGet, "workspaces/*/groups", s.listWorkspaceGroups),
+		r(http.MethodGet, "workspaces/*/images", s.listWorkspaceImages),
+		r(http.MethodGet, "workspaces/*/secrets", s.listSecrets),
+		r(http.MethodGet, "workspaces/*/secrets/*", s.getSecret),
+		r(http.MethodDelete, "workspaces/*/secrets/*", s.deleteSecret),
+		r(http.MethodGet, "workspaces/*/secrets/*/value", s.readSecret),
+		r(http.MethodPut, "workspaces/*/secrets/*/value", s.putSecret),
+
+		r(http.MethodPost, "datasets", s.createDataset),
+		r(http.MethodPost, "datasets/search", s.notImplemented),
+		r(http.MethodGet, "datasets/*", s.getDataset),
+		r(http.MethodPatch, "datasets/*", s.patchDataset),
+		r(http.MethodDelete, "datasets/*", s.deleteDataset),
+
+		r(http.MethodPost, "experiments/search", s.notImplemented),
+		r(http.MethodGet, "experiments/*", s.getExperiment),
+		r(http.MethodPatch, "experiments/*", s.patchExperiment),
+		r(http.MethodDelete, "experiments/*", s.deleteExperiment),
+		r(http.MethodGet, "experiments/*/groups", s.listExperimentGroups),
+		r(http.MethodGet, "experiments/*/spec", s.getExperimentSpec),
+		r(http.MethodPost, "experiments/*/resume", s.resumeExperiment),
+		r(http.MethodPut, "experiments/*/stop", s.stopExperiment),
+		r(http.MethodGet, "experiments/*/tasks", s.listExperimentTasks),
+		r(http.MethodGet, "tasks/*", s.getTask),
+
+		r(http.MethodGet, "executions/*", s.getExecution),
+		r(http.MethodGet, "executions/*/logs", s.getLogs),
+		r(http.MethodPut, "executions/*/logs/*", s.putLogs),
+		r(http.MethodGet, "executions/*/results", s.getResults),
+		r(http.MethodPost, "executions/*/status", s.postStatus),
+		r(http.MethodPost, "executions/*/stop", s.stopExecution),
+
+		r(http.MethodPost, "groups", s.createGroup),
+		r(http.MethodGet, "groups/*", s.getGroup),
+		r(http.MethodPatch, "groups/*", s.patchGroup),
+		r(http.MethodDelete, "groups/*", s.deleteGroup),
+		r(http.MethodGet, "groups/*/experiments", s.listGroupExperiments),
+
+		r(http.MethodPost, "images", s.createImage),
+		r(http.MethodPost, "images/search", s.notImplemented),
+		r(http.MethodGet, "images/*", s.getImage),
+		r(http.MethodPatch, "images/*", s.patchImage),
+		r(http.MethodDelete, "images/*", s.deleteImage),
+		r(http.MethodGet, "images/*/repository", s.getImageRepository),
+
+		r(http.MethodPost, "clusters/*", s.createCluster),
+		r(http.MethodGet, "clusters/*", s.listClusters),
+		r(http.MethodGet, "clusters/*/*", s.getCluster),
+		r(http.MethodPatch, "clusters/*/*", s.patchCluster),
+		r(http.MethodDelete, "clusters/*/*", s.terminateCluster),
+		r(http.MethodPost, "clusters/*/*/nodes", s.createNode),
+		r(http.MethodGet, "clusters/*/*/nodes", s.listClusterNodes),
+		r(http.MethodGet, "clusters/*/*/executions", s.listClusterExecutions),
+		r(http.MethodPatch, "clusters/*/*/executions/*", s.patchClusterExecution),
+
+		r(http.MethodGet, "nodes/*", s.getNode),
+		r(http.MethodPatch, "nodes/*", s.patchNode),
+		r(http.MethodDelete, "nodes/*", s.deleteNode),
+		r(http.MethodGet, "nodes/*/executions", s.listNodeExecutions),
+		r(http.MethodPost, "nodes/*/executions", s.assignExecutions),
+
+		r(http.MethodGet, "reports/nodes", s.getNodeUsageReport),
+		r(http.MethodGet, "reports/tasks", s.getTaskUsageReport),
+
+		r(http.MethodPost, "sessions", s.createSession),
+		r(http.MethodGet, "sessions", s.listSessions),
+		r(http.MethodGet, "sessions/*", s.getSession),
+		r(http.MethodPatch, "sessions/*", s.patchSession),
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.Token {
+		s.writeError(w, errorf(http.StatusUnauthorized, "invalid or missing authentication token"))
+		return
+	}
+
+	const prefix = "/api/v3/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		s.writeError(w, errorf(http.StatusNotFound, "%s not found", r.URL.Path))
+		return
+	}
+
+	// Segments are escaped by the client, so unescape them individually to
+	// preserve slashes within references such as "account/name".
+	segments := strings.Split(strings.Trim(r.URL.Path[len(prefix):], "/"), "/")
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segments[i] = unescaped
+		}
+	}
+
+	var h handler
+	var args []string
+	pathMatched := false
+	for _, rt := range s.routes {
+		if a, ok := matchRoute(rt.pattern, segments); ok {
+			pathMatched = true
+			if rt.method == r.Method {
+				h, args = rt.handle, a
+				break
+			}
+		}
+	}
+	if h == nil {
+		if pathMatched {
+			s.writeError(w, errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method))
+		} else {
+			s.writeError(w, errorf(http.StatusNotFound, "%s not found", r.URL.Path))
+		}
+		return
+	}
+
+	s.mu.Lock()
+	result, err := h(r, args)
+	s.mu.Unlock()
+
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	switch v := result.(type) {
+	case nil:
+		w.WriteHeader(http.StatusOK)
+	case rawResponse:
+		w.Header().Set("Content-Type", v.contentType)
+		_, _ = w.Write(v.body)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+func matchRoute(pattern, segments []string) ([]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	var args []string
+	for i, p := range pattern {
+		switch {
+		case p == "*":
+			args = append(args, segments[i])
+		case p != segments[i]:
+			return nil, false
+		}
+	}
+	return args, true
+}
+
+func (s *Server) notImplemented(r *http.Request, args []string) (interface{}, error) {
+	return nil, errorf(http.StatusNotImplemented, "%s is not supported by the fake server", r.URL.Path)
+}
+
+// errorf creates an error as returned by the service.
+func errorf(code int, format string, args ...interface{}) error {
+	return api.Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(kind, ref string) error {
+	return errorf(http.StatusNotFound, "%s %q not found", kind, ref)
+}
+
+func conflict(kind, name string) error {
+	return errorf(http.StatusConflict, "%s %q already exists", kind, name)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(api.Error)
+	if !ok {
+		apiErr = api.Error{Code: http.StatusInternalServerError, Message: err.Error()}
+	}
+	apiErr.ErrorID = fmt.Sprintf("fake-%d", atomic.AddInt64(&s.errorCount, 1))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.Code)
+	_ = json.NewEncoder(w).Encode(apiErr)
+}
+
+// decodeBody parses a request's JSON body into v. An empty body is allowed.
+func decodeBody(r *http.Request, v interface{}) error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "failed to read request body: %v", err)
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+// paginate selects the window of n items requested by the "cursor" and
+// "limit" query parameters. It returns the bounds of the window and a cursor
+// to the next page, which is empty on the last page.
+func paginate(r *http.Request, n int) (start, end int, next string, err error) {
+	query := r.URL.Query()
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		b, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err == nil {
+			start, err = strconv.Atoi(string(b))
+		}
+		if err != nil || start < 0 {
+			return 0, 0, "", errorf(http.StatusBadRequest, "invalid cursor %q", cursor)
+		}
+	}
+
+	limit := DefaultPageSize
+	if l := query.Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 0 {
+			return 0, 0, "", errorf(http.StatusBadRequest, "invalid limit %q", l)
+		}
+		if limit == 0 {
+			limit = DefaultPageSize
+		}
+		if limit > MaxPageSize {
+			limit = MaxPageSize
+		}
+	}
+
+	if start > n {
+		start = n
+	}
+	end = start + limit
+	if end >= n {
+		return start, n, "", nil
+	}
+	return start, end, base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(end))), nil
+}
+
+// parseBool parses an optional boolean query parameter.
+func parseBool(r *http.Request, key string) (*bool, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "invalid value %q for %s", v, key)
+	}
+	return &b, nil
+}
+
+// matchesText returns whether any of the given fields contain the "q" query
+// parameter, if present.
+func matchesText(r *http.Request, fields ...string) bool {
+	q := strings.ToLower(r.URL.Query().Get("q"))
+	if q == "" {
+		return true
+	}
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), q) {
+			return true
+		}
+	}
+	return false
+}
+
+// itemName resolves a reference to a dataset, experiment, group, or image as
+// the service does: unqualified names belong to the authenticated user.
+func (s *Server) itemName(ref string) string {
+	if strings.Contains(ref, "/") {
+		return ref
+	}
+	return fullName(s.User.Name, ref)
+}
+
+// fullName qualifies a name by its owning account.
+func fullName(account, name string) string {
+	if name == "" {
+		return ""
+	}
+	return account + "/" + name
+}
@@ -0,0 +1,74 @@
+package fake
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beaker/client/api"
+)
+
+func TestPaginate(t *testing.T) {
+	cursor := func(i int) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(i)))
+	}
+
+	cases := map[string]struct {
+		Query string
+		N     int
+		Start int
+		End   int
+		Next  string
+		Error bool
+	}{
+		"Empty":         {N: 0, Start: 0, End: 0},
+		"DefaultLimit":  {N: DefaultPageSize + 1, Start: 0, End: DefaultPageSize, Next: cursor(DefaultPageSize)},
+		"ZeroLimit":     {Query: "limit=0", N: DefaultPageSize + 1, Start: 0, End: DefaultPageSize, Next: cursor(DefaultPageSize)},
+		"ExactLastPage": {Query: "limit=5", N: 5, Start: 0, End: 5},
+		"MaxPageSize":   {Query: "limit=5000", N: MaxPageSize + 1, Start: 0, End: MaxPageSize, Next: cursor(MaxPageSize)},
+		"FullMaxPage":   {Query: "limit=1000", N: MaxPageSize, Start: 0, End: MaxPageSize},
+		"Cursor":        {Query: "limit=2&cursor=" + cursor(2), N: 5, Start: 2, End: 4, Next: cursor(4)},
+		"PastEnd":       {Query: "cursor=" + cursor(10), N: 5, Start: 5, End: 5},
+		"BadCursor":     {Query: "cursor=!!!", N: 5, Error: true},
+		"NonNumeric":    {Query: "cursor=" + base64.RawURLEncoding.EncodeToString([]byte("x")), N: 5, Error: true},
+		"Negative":      {Query: "cursor=" + cursor(-1), N: 5, Error: true},
+		"BadLimit":      {Query: "limit=-1", N: 5, Error: true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v3/workspaces?"+c.Query, nil)
+			start, end, next, err := paginate(r, c.N)
+			if c.Error {
+				require.Error(t, err)
+				assert.Equal(t, http.StatusBadRequest, err.(api.Error).Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.Start, start)
+			assert.Equal(t, c.End, end)
+			assert.Equal(t, c.Next, next)
+		})
+	}
+}
+
+func TestFilterLogs(t *testing.T) {
+	base := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	stamp := func(d time.Duration) string { return base.Add(d).Format(time.RFC3339Nano) }
+	logs := []byte(stamp(0) + " first\n" +
+		"  continued\n" +
+		stamp(time.Second) + " second\n" +
+		"  continued\n" +
+		stamp(2*time.Second) + " third")
+
+	assert.Equal(t, string(logs), string(filterLogs(logs, time.Time{})))
+	assert.Equal(t, stamp(time.Second)+" second\n  continued\n"+stamp(2*time.Second)+" third",
+		string(filterLogs(logs, base.Add(time.Second))))
+	assert.Equal(t, stamp(2*time.Second)+" third", string(filterLogs(logs, base.Add(1500*time.Millisecond))))
+	assert.Empty(t, filterLogs(logs, base.Add(time.Hour)))
+}
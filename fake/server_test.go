@@ -0,0 +1,435 @@
+package fake_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beaker/client/api"
+	"github.com/beaker/client/client"
+	"github.com/beaker/client/fake"
+)
+
+func newClient(t *testing.T) (*fake.Server, *client.Client) {
+	server := fake.NewServer()
+	t.Cleanup(server.Close)
+
+	c, err := client.NewClient(server.URL, server.Token)
+	require.NoError(t, err)
+	return server, c
+}
+
+func TestAuthentication(t *testing.T) {
+	server, _ := newClient(t)
+
+	c, err := client.NewClient(server.URL, "wrong")
+	require.NoError(t, err)
+	_, err = c.WhoAmI(context.Background())
+	assert.True(t, client.IsUnauthorized(err))
+	assert.NotEmpty(t, client.ErrorID(err))
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	_, c := newClient(t)
+
+	_, err := c.Dataset("user/missing").Get(ctx)
+	assert.True(t, client.IsNotFound(err))
+	assert.Contains(t, err.Error(), `dataset "user/missing" not found`)
+	assert.Equal(t, "fake-1", client.ErrorID(err))
+
+	workspace, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: "ws"})
+	require.NoError(t, err)
+	_, err = c.CreateDataset(ctx, api.DatasetSpec{Workspace: workspace.Ref()}, "data")
+	require.NoError(t, err)
+	_, err = c.CreateDataset(ctx, api.DatasetSpec{Workspace: workspace.Ref()}, "data")
+	assert.True(t, client.IsConflict(err))
+	assert.Contains(t, err.Error(), `dataset "user/data" already exists`)
+	assert.Equal(t, "fake-2", client.ErrorID(err), "each error should have a new ID")
+
+	_, err = c.CreateDataset(ctx, api.DatasetSpec{}, "")
+	assert.Equal(t, http.StatusBadRequest, client.StatusCode(err))
+	assert.Equal(t, "fake-3", client.ErrorID(err))
+}
+
+func TestItemRefs(t *testing.T) {
+	ctx := context.Background()
+	_, c := newClient(t)
+
+	workspace, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: "ws"})
+	require.NoError(t, err)
+	dataset, err := c.CreateDataset(ctx, api.DatasetSpec{Workspace: workspace.Ref()}, "data")
+	require.NoError(t, err)
+	image, err := c.CreateImage(ctx, api.ImageSpec{Workspace: workspace.Ref(), ImageTag: "busybox"}, "img")
+	require.NoError(t, err)
+	group, err := c.CreateGroup(ctx, api.GroupSpec{Workspace: workspace.Ref(), Name: "grp"})
+	require.NoError(t, err)
+
+	// Items may be referred to by ID, full name, or a name relative to the user.
+	for _, ref := range []string{dataset.Ref(), "user/data", "data"} {
+		d, err := c.Dataset(ref).Get(ctx)
+		require.NoError(t, err, ref)
+		assert.Equal(t, "user/data", d.FullName)
+	}
+	for _, ref := range []string{image.Ref(), "user/img", "img"} {
+		i, err := c.Image(ref).Get(ctx)
+		require.NoError(t, err, ref)
+		assert.Equal(t, "user/img", i.FullName)
+	}
+	for _, ref := range []string{group.Ref(), "user/grp", "grp"} {
+		g, err := c.Group(ref).Get(ctx)
+		require.NoError(t, err, ref)
+		assert.Equal(t, "user/grp", g.FullName)
+	}
+
+	// Names of other accounts and workspaces don't match.
+	for _, ref := range []string{"org/data", "ws/data", "user/data/extra"} {
+		_, err := c.Dataset(ref).Get(ctx)
+		assert.True(t, client.IsNotFound(err), ref)
+	}
+}
+
+func TestWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	server, c := newClient(t)
+
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: name})
+		require.NoError(t, err)
+	}
+
+	_, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: "a"})
+	assert.True(t, client.IsConflict(err))
+
+	iter := c.IterateWorkspaces(ctx, server.Org.Name, nil, &client.IteratorOptions{PageSize: 2})
+	var names []string
+	for iter.Next() {
+		names = append(names, iter.Value().FullName)
+	}
+	require.NoError(t, iter.Err())
+	assert.Equal(t, []string{"org/a", "org/b", "org/c"}, names)
+
+	workspace := c.Workspace("org/b")
+	require.NoError(t, workspace.SetName(ctx, "renamed"))
+	_, err = workspace.Get(ctx)
+	assert.True(t, client.IsNotFound(err))
+
+	_, err = c.Workspace("org/renamed").PutSecret(ctx, "key", []byte("value"))
+	require.NoError(t, err)
+	value, err := c.Workspace("org/renamed").ReadSecret(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", string(value))
+}
+
+func TestExperimentLifecycle(t *testing.T) {
+	ctx := context.Background()
+	_, c := newClient(t)
+
+	workspace, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: "ws"})
+	require.NoError(t, err)
+
+	_, err = workspace.CreateExperiment(ctx, &api.ExperimentSpecV2{Version: "v2-alpha"}, nil)
+	assert.Equal(t, 400, client.StatusCode(err))
+
+	spec := &api.ExperimentSpecV2{
+		Version: "v2-alpha",
+		Tasks: []api.TaskSpecV2{{
+			Name:    "main",
+			Image:   api.ImageSource{Docker: "busybox"},
+			Result:  api.ResultSpec{Path: "/out"},
+			Context: api.Context{Cluster: "org/cpu"},
+		}},
+	}
+	created, err := workspace.CreateExperiment(ctx, spec, &client.ExperimentOpts{Name: "exp"})
+	require.NoError(t, err)
+	require.Len(t, created.Executions, 1)
+	assert.Equal(t, "user/exp", created.FullName)
+
+	execution := c.Execution(created.Executions[0].ID)
+	base := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	logs := base.Format(time.RFC3339Nano) + " first\n" + base.Add(time.Second).Format(time.RFC3339Nano) + " second\n"
+	require.NoError(t, execution.PutLogs(ctx, "log.txt", strings.NewReader(logs)))
+
+	exitCode := 0
+	require.NoError(t, execution.PostStatus(ctx, api.ExecStatusUpdate{Started: true}))
+	require.NoError(t, execution.PostStatus(ctx, api.ExecStatusUpdate{ExitCode: &exitCode, Finalized: true}))
+
+	results, err := c.Experiment("user/exp").Wait(ctx, &client.WaitOptions{PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Succeeded())
+
+	reader, err := execution.Logs(ctx, &client.LogFilter{Since: base.Add(time.Second)})
+	require.NoError(t, err)
+	defer reader.Close()
+	require.True(t, reader.Next())
+	assert.Equal(t, "second", reader.Value().Message)
+	assert.False(t, reader.Next())
+
+	require.NoError(t, c.Experiment(created.ID).Delete(ctx))
+	_, err = execution.Get(ctx)
+	assert.True(t, client.IsNotFound(err))
+}
+
+func TestSweep(t *testing.T) {
+	ctx := context.Background()
+	_, c := newClient(t)
+
+	workspace, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: "ws"})
+	require.NoError(t, err)
+
+	spec := &api.ExperimentSpecV2{
+		Version: "v2-alpha",
+		Tasks: []api.TaskSpecV2{{
+			Name:    "train",
+			Image:   api.ImageSource{Docker: "busybox"},
+			Result:  api.ResultSpec{Path: "/out"},
+			Context: api.Context{Cluster: "org/cpu"},
+		}},
+	}
+	sweep := &api.Sweep{Parameters: []api.SweepParameter{
+		{EnvVar: "LR", Values: []string{"0.1", "0.01", "0.001"}},
+	}}
+	result, err := workspace.CreateSweep(ctx, spec, sweep, &client.SweepOptions{
+		Name:                  "lr",
+		MaxTasksPerExperiment: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Experiments, 2)
+	assert.Equal(t, "user/lr-0", result.Experiments[0].FullName)
+	assert.Len(t, result.Experiments[0].Executions, 2)
+	assert.Len(t, result.Experiments[1].Executions, 1)
+
+	experiments, err := result.Group.Experiments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{result.Experiments[0].ID, result.Experiments[1].ID}, experiments)
+
+	_, err = workspace.CreateSweep(ctx, spec, &api.Sweep{}, &client.SweepOptions{Name: "empty"})
+	assert.Error(t, err)
+
+	_, err = workspace.CreateSweep(ctx, nil, sweep, &client.SweepOptions{Name: "nil-base"})
+	assert.EqualError(t, err, "base spec is required")
+	_, err = workspace.CreateSweep(ctx, spec, nil, &client.SweepOptions{Name: "nil-sweep"})
+	assert.EqualError(t, err, "sweep is required")
+}
+
+func TestCloneExperiment(t *testing.T) {
+	ctx := context.Background()
+	_, c := newClient(t)
+
+	workspace, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: "ws"})
+	require.NoError(t, err)
+	other, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: "other"})
+	require.NoError(t, err)
+
+	value := "1"
+	spec := &api.ExperimentSpecV2{
+		Version: "v2-alpha",
+		Tasks: []api.TaskSpecV2{{
+			Name:      "main",
+			Image:     api.ImageSource{Docker: "busybox"},
+			Arguments: []string{"run"},
+			EnvVars:   []api.EnvironmentVariable{{Name: "A", Value: &value}},
+			Result:    api.ResultSpec{Path: "/out"},
+			Context:   api.Context{Cluster: "org/cpu"},
+		}},
+	}
+	source, err := workspace.CreateExperiment(ctx, spec, &client.ExperimentOpts{Name: "exp"})
+	require.NoError(t, err)
+
+	two := "2"
+	clone, err := c.Experiment(source.ID).Clone(ctx, "", &client.CloneOverrides{
+		AllTasks: client.TaskOverrides{Cluster: "org/gpu", Priority: api.HighPriority},
+		Tasks: map[string]client.TaskOverrides{"main": {
+			EnvVars:   []api.EnvironmentVariable{{Name: "A", Value: &two}},
+			Arguments: []string{"eval"},
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "user/exp-clone", clone.FullName)
+	assert.Equal(t, source.Workspace.ID, clone.Workspace.ID)
+	assert.Equal(t, "Cloned from experiment user/exp ("+source.ID+")", clone.Description)
+
+	body, err := c.Experiment(clone.ID).Spec(ctx, "v2", false)
+	require.NoError(t, err)
+	defer body.Close()
+	b, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+	cloned, err := api.ParseExperimentSpec(b)
+	require.NoError(t, err)
+	task := cloned.Tasks[0]
+	assert.Equal(t, api.Context{Cluster: "org/gpu", Priority: api.HighPriority}, task.Context)
+	assert.Equal(t, []string{"eval"}, task.Arguments)
+	assert.Equal(t, "2", *task.EnvVars[0].Value)
+
+	// Later clones take the next free name.
+	clone, err = c.Experiment(source.ID).Clone(ctx, other.Ref(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "user/exp-clone-2", clone.FullName)
+	assert.Equal(t, "org/other", clone.Workspace.FullName)
+
+	_, err = c.Experiment(source.ID).Clone(ctx, "", &client.CloneOverrides{
+		Tasks: map[string]client.TaskOverrides{"missing": {}},
+	})
+	assert.EqualError(t, err, `can't override missing tasks: "missing"`)
+}
+
+func TestDefaultWorkspace(t *testing.T) {
+	ctx := context.Background()
+	server, c := newClient(t)
+
+	_, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: "ws"})
+	require.NoError(t, err)
+	other, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: "other"})
+	require.NoError(t, err)
+	_, err = c.CreateDataset(ctx, api.DatasetSpec{Workspace: other.Ref()}, "other-data")
+	require.NoError(t, err)
+
+	scoped, err := client.NewClient(server.URL, server.Token, client.WithDefaultWorkspace("org/ws"))
+	require.NoError(t, err)
+	_, err = scoped.CreateDataset(ctx, api.DatasetSpec{}, "my-data")
+	require.NoError(t, err)
+	_, err = scoped.CreateGroup(ctx, api.GroupSpec{Name: "my-group"})
+	require.NoError(t, err)
+	_, err = scoped.CreateImage(ctx, api.ImageSpec{ImageTag: "busybox"}, "my-image")
+	require.NoError(t, err)
+
+	// Unqualified names refer to items in the default workspace.
+	dataset, err := scoped.Dataset("my-data").Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "user/my-data", dataset.FullName)
+	assert.Equal(t, "org/ws", dataset.Workspace.FullName)
+	group, err := scoped.Group("my-group").Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "org/ws", group.Workspace.FullName)
+	image, err := scoped.Image("my-image").Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "org/ws", image.Workspace.FullName)
+
+	// Handles resolve the name for every request, not just Get.
+	require.NoError(t, scoped.Dataset("my-data").SetDescription(ctx, "scoped"))
+	dataset, err = c.Dataset("user/my-data").Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "scoped", dataset.Description)
+
+	// Names outside the default workspace aren't found, though the service
+	// would find them relative to the user.
+	_, err = scoped.Dataset("other-data").Get(ctx)
+	assert.True(t, client.IsNotFound(err))
+	_, err = c.Dataset("other-data").Get(ctx)
+	require.NoError(t, err)
+
+	// Qualified names and IDs are passed through.
+	_, err = scoped.Dataset("user/other-data").Get(ctx)
+	require.NoError(t, err)
+	_, err = scoped.Dataset("org/my-data").Get(ctx)
+	assert.True(t, client.IsNotFound(err))
+}
+
+func TestUsageReports(t *testing.T) {
+	ctx := context.Background()
+	server, c := newClient(t)
+
+	_, err := c.CreateCluster(ctx, "org", api.ClusterSpec{
+		Name:     "gpu",
+		Capacity: 2,
+		Spec:     &api.NodeResources{CPUCount: 32, GPUCount: 4},
+	})
+	require.NoError(t, err)
+	cost := decimal.RequireFromString("12.24")
+	gpu, err := c.Cluster("org/gpu").Patch(ctx, &api.ClusterPatch{NodeCost: &cost})
+	require.NoError(t, err)
+	workspace, err := c.CreateWorkspace(ctx, api.WorkspaceSpec{Name: "ws"})
+	require.NoError(t, err)
+
+	jan := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	mar := feb.AddDate(0, 1, 0)
+	four := 4
+	server.SetNodeUsage([]api.NodeUsageSeries{
+		{
+			Node: "n1", Cluster: "org/gpu", Preemptible: api.BoolPtr(true), GPUCount: &four,
+			Intervals: []api.UsageInterval{
+				{Start: jan, End: feb, Value: 10},
+				{Start: feb, End: mar, Value: 20},
+				{Start: mar, End: mar.AddDate(0, 1, 0), Value: 100},
+			},
+		},
+		{
+			Node: "n2", Cluster: "org/gpu", Preemptible: api.BoolPtr(true), GPUCount: &four,
+			Intervals: []api.UsageInterval{{Start: jan, End: feb, Value: 5}},
+		},
+		{Node: "n3", Cluster: "user/cpu", Intervals: []api.UsageInterval{{Start: jan, End: feb, Value: 7}}},
+	})
+	server.SetTaskUsage([]api.TaskUsageSeries{
+		{Workspace: workspace.Ref(), Cluster: "org/gpu", Author: "alice", Intervals: []api.UsageInterval{{Start: jan, End: feb, Value: 2}}},
+		{Workspace: workspace.Ref(), Cluster: "org/gpu", Author: "bob", Intervals: []api.UsageInterval{{Start: feb, End: mar, Value: 3}}},
+		{Workspace: "elsewhere", Cluster: "org/gpu", Author: "bob", Intervals: []api.UsageInterval{{Start: feb, End: mar, Value: 50}}},
+	})
+	calc := api.NewCostCalculator([]api.Cluster{*gpu})
+
+	nodes, err := c.Organization("org").NodeUsageReport(ctx, &client.NodeUsageOptions{
+		Start:    jan,
+		End:      mar,
+		Interval: api.IntervalMonth,
+		Metric:   api.NodeMetricGPUHours,
+		GroupBy:  []api.NodeGroupBy{api.NodeGroupByCluster, api.NodeGroupByPreemptible},
+	})
+	require.NoError(t, err)
+	require.Len(t, nodes.Series, 1)
+	assert.Empty(t, nodes.Series[0].Node)
+	assert.Equal(t, 35.0, nodes.Totals.Value)
+
+	var csv bytes.Buffer
+	require.NoError(t, nodes.Table().WriteCSV(&csv))
+	assert.Equal(t, `kind,start,end,node,cluster,preemptible,autoscale,gpuCount,gpuType,value
+interval,2021-01-01T00:00:00Z,2021-02-01T00:00:00Z,,org/gpu,true,,,,15
+interval,2021-02-01T00:00:00Z,2021-03-01T00:00:00Z,,org/gpu,true,,,,20
+seriesTotal,2021-01-01T00:00:00Z,2021-03-01T00:00:00Z,,org/gpu,true,,,,35
+total,2021-01-01T00:00:00Z,2021-03-01T00:00:00Z,,,,,,,35
+`, csv.String())
+
+	nodeCost, err := calc.NodeUsageCost(nodes, api.NodeMetricGPUHours)
+	require.NoError(t, err)
+	assert.Equal(t, "107.1", nodeCost.Total.String())
+
+	_, err = c.CreateCluster(ctx, "user", api.ClusterSpec{Name: "cpu"})
+	require.NoError(t, err)
+	nodes, err = c.Cluster("user/cpu").NodeUsageReport(ctx, &client.NodeUsageOptions{Start: jan, End: mar})
+	require.NoError(t, err)
+	assert.Equal(t, 7.0, nodes.Totals.Value)
+	nodes, err = c.NodeUsageReport(ctx, &client.NodeUsageOptions{Start: jan, End: mar})
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, nodes.Totals.Value)
+
+	tasks, err := workspace.TaskUsageReport(ctx, &client.TaskUsageOptions{
+		Start:   jan,
+		End:     mar,
+		Metric:  api.TaskMetricGPUHours,
+		GroupBy: []api.TaskGroupBy{api.TaskGroupByCluster, api.TaskGroupByAuthor},
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks.Series, 2)
+	assert.Equal(t, 5.0, tasks.Totals.Value)
+
+	taskCost, err := calc.TaskUsageCost(tasks, api.TaskMetricGPUHours)
+	require.NoError(t, err)
+	assert.Equal(t, "6.12", taskCost.ByAuthor["alice"].String())
+	assert.Equal(t, "9.18", taskCost.ByAuthor["bob"].String())
+
+	// The service rejects dimensions which don't apply to the report.
+	_, err = c.TaskUsageReport(ctx, &client.TaskUsageOptions{
+		Start:   jan,
+		End:     mar,
+		GroupBy: []api.TaskGroupBy{api.TaskGroupBy(api.NodeGroupByPreemptible)},
+	})
+	assert.Equal(t, http.StatusBadRequest, client.StatusCode(err))
+}
@@ -0,0 +1,101 @@
+package fake
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/beaker/client/api"
+)
+
+func (s *Server) findSession(id string) (*api.Session, error) {
+	for _, session := range s.sessions {
+		if session.ID == id {
+			return session, nil
+		}
+	}
+	return nil, notFound("session", id)
+}
+
+func (s *Server) createSession(r *http.Request, args []string) (interface{}, error) {
+	var spec api.SessionSpec
+	if err := decodeBody(r, &spec); err != nil {
+		return nil, err
+	}
+	if spec.Node == "" {
+		return nil, errorf(http.StatusBadRequest, "node is required")
+	}
+	n, err := s.findNode(spec.Node)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.findCluster(n.cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	t := now()
+	session := &api.Session{
+		ID:       s.newID(),
+		Name:     spec.Name,
+		Cluster:  c.Name,
+		Account:  strings.TrimSuffix(c.FullName, "/"+c.Name),
+		Author:   s.User.Identity,
+		Node:     n.ID,
+		State:    api.ExecutionState{Created: t, Scheduled: &t},
+		Requests: spec.Requests,
+	}
+	s.sessions = append(s.sessions, session)
+	return *session, nil
+}
+
+func (s *Server) listSessions(r *http.Request, args []string) (interface{}, error) {
+	query := r.URL.Query()
+	finalized, err := parseBool(r, "finalized")
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []api.Session{}
+	for _, session := range s.sessions {
+		if node := query.Get("node"); node != "" && session.Node != node {
+			continue
+		}
+		if cluster := query.Get("cluster"); cluster != "" &&
+			cluster != session.Cluster && cluster != fullName(session.Account, session.Cluster) {
+			continue
+		}
+		if finalized != nil && (session.State.Finalized != nil) != *finalized {
+			continue
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, nil
+}
+
+func (s *Server) getSession(r *http.Request, args []string) (interface{}, error) {
+	session, err := s.findSession(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return *session, nil
+}
+
+func (s *Server) patchSession(r *http.Request, args []string) (interface{}, error) {
+	session, err := s.findSession(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var patch api.SessionPatch
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+
+	if patch.State != nil {
+		applyStatus(&session.State, *patch.State)
+	}
+	if patch.Limits != nil {
+		session.Limits = patch.Limits
+	}
+	return *session, nil
+}
@@ -0,0 +1,486 @@
+package fake
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/beaker/client/api"
+)
+
+type workspace struct {
+	api.Workspace
+	org     string // ID of the owning organization.
+	public  bool
+	auth    map[string]api.Permission
+	secrets []*secret
+}
+
+type secret struct {
+	api.Secret
+	value []byte
+}
+
+func (s *Server) findWorkspace(ref string) (*workspace, error) {
+	for _, w := range s.workspaces {
+		if w.ID == ref || w.FullName == ref {
+			return w, nil
+		}
+	}
+	return nil, notFound("workspace", ref)
+}
+
+func (w *workspace) findSecret(name string) (int, error) {
+	for i, secret := range w.secrets {
+		if secret.Name == name {
+			return i, nil
+		}
+	}
+	return 0, notFound("secret", name)
+}
+
+func (w *workspace) ref() api.WorkspaceReference {
+	return api.WorkspaceReference{ID: w.ID, Name: w.Name, FullName: w.FullName}
+}
+
+// view returns a workspace as the service presents it.
+func (s *Server) viewWorkspace(w *workspace) api.Workspace {
+	view := w.Workspace
+	view.Size = api.WorkspaceItemCount{}
+	for _, d := range s.datasets {
+		if d.Workspace.ID == w.ID {
+			view.Size.Datasets++
+		}
+	}
+	for _, e := range s.experiments {
+		if e.Workspace.ID == w.ID {
+			view.Size.Experiments++
+		}
+	}
+	for _, g := range s.groups {
+		if g.Workspace.ID == w.ID {
+			view.Size.Groups++
+		}
+	}
+	for _, i := range s.images {
+		if i.Workspace.ID == w.ID {
+			view.Size.Images++
+		}
+	}
+	return view
+}
+
+func (s *Server) createWorkspace(r *http.Request, args []string) (interface{}, error) {
+	var spec api.WorkspaceSpec
+	if err := decodeBody(r, &spec); err != nil {
+		return nil, err
+	}
+	if spec.Name == "" {
+		return nil, errorf(http.StatusBadRequest, "workspace name is required")
+	}
+
+	org := s.orgs[0]
+	if spec.Organization != "" {
+		var err error
+		if org, err = s.findOrg(spec.Organization); err != nil {
+			return nil, err
+		}
+	}
+
+	name := fullName(org.Name, spec.Name)
+	if _, err := s.findWorkspace(name); err == nil {
+		return nil, conflict("workspace", name)
+	}
+
+	t := now()
+	w := &workspace{
+		Workspace: api.Workspace{
+			ID:          s.newID(),
+			Name:        spec.Name,
+			FullName:    name,
+			Description: spec.Description,
+			Owner:       org.Identity,
+			Author:      s.User.Identity,
+			Created:     t,
+			Modified:    t,
+		},
+		org:    org.ID,
+		public: spec.Public,
+		auth:   map[string]api.Permission{s.User.ID: api.FullControl},
+	}
+	s.workspaces = append(s.workspaces, w)
+	return s.viewWorkspace(w), nil
+}
+
+func (s *Server) listWorkspaces(r *http.Request, args []string) (interface{}, error) {
+	org := s.orgs[0]
+	if ref := r.URL.Query().Get("org"); ref != "" {
+		var err error
+		if org, err = s.findOrg(ref); err != nil {
+			return nil, err
+		}
+	}
+	archived, err := parseBool(r, "archived")
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*workspace
+	for _, w := range s.workspaces {
+		if w.org != org.ID || (archived != nil && w.Archived != *archived) {
+			continue
+		}
+		if matchesText(r, w.Name, w.Description) {
+			matches = append(matches, w)
+		}
+	}
+
+	start, end, next, err := paginate(r, len(matches))
+	if err != nil {
+		return nil, err
+	}
+
+	page := api.WorkspacePage{Data: []api.Workspace{}, NextCursor: next, Organization: org.Name}
+	for _, w := range matches[start:end] {
+		page.Data = append(page.Data, s.viewWorkspace(w))
+	}
+	return page, nil
+}
+
+func (s *Server) getWorkspace(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.viewWorkspace(w), nil
+}
+
+func (s *Server) patchWorkspace(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var patch api.WorkspacePatchSpec
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil {
+		name := fullName(w.Owner.Name, *patch.Name)
+		if other, err := s.findWorkspace(name); err == nil && other != w {
+			return nil, conflict("workspace", name)
+		}
+		w.Name, w.FullName = *patch.Name, name
+		s.renameWorkspace(w)
+	}
+	if patch.Description != nil {
+		w.Description = *patch.Description
+	}
+	if patch.Archive != nil {
+		w.Archived = *patch.Archive
+	}
+	w.Modified = now()
+	return s.viewWorkspace(w), nil
+}
+
+// renameWorkspace updates references to a workspace after its name changes.
+func (s *Server) renameWorkspace(w *workspace) {
+	ref := w.ref()
+	for _, d := range s.datasets {
+		if d.Workspace.ID == w.ID {
+			d.Workspace = ref
+		}
+	}
+	for _, e := range s.experiments {
+		if e.Workspace.ID == w.ID {
+			e.Workspace = ref
+		}
+	}
+	for _, g := range s.groups {
+		if g.Workspace.ID == w.ID {
+			g.Workspace = ref
+		}
+	}
+	for _, i := range s.images {
+		if i.Workspace.ID == w.ID {
+			i.Workspace = ref
+		}
+	}
+}
+
+func (s *Server) transferToWorkspace(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if w.Archived {
+		return nil, errorf(http.StatusForbidden, "workspace %q is archived", w.FullName)
+	}
+
+	var spec api.WorkspaceTransferSpec
+	if err := decodeBody(r, &spec); err != nil {
+		return nil, err
+	}
+
+	// Resolve all items before moving any so that a transfer is atomic.
+	var refs []*api.WorkspaceReference
+	for _, id := range spec.IDs {
+		ref := s.findWorkspaceItem(id)
+		if ref == nil {
+			return nil, notFound("item", id)
+		}
+		refs = append(refs, ref)
+	}
+	for _, ref := range refs {
+		*ref = w.ref()
+	}
+	return nil, nil
+}
+
+// findWorkspaceItem returns the workspace of the dataset, experiment, group,
+// or image with the given ID, or nil if none exists.
+func (s *Server) findWorkspaceItem(id string) *api.WorkspaceReference {
+	for _, d := range s.datasets {
+		if d.ID == id {
+			return &d.Workspace
+		}
+	}
+	for _, e := range s.experiments {
+		if e.ID == id {
+			return &e.Workspace
+		}
+	}
+	for _, g := range s.groups {
+		if g.ID == id {
+			return &g.Workspace
+		}
+	}
+	for _, i := range s.images {
+		if i.ID == id {
+			return &i.Workspace
+		}
+	}
+	return nil
+}
+
+func (s *Server) getWorkspacePermissions(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	auth := make(map[string]api.Permission, len(w.auth))
+	for k, v := range w.auth {
+		auth[k] = v
+	}
+	return api.WorkspacePermissionSummary{
+		RequesterAuth:  w.auth[s.User.ID],
+		Public:         w.public,
+		Authorizations: auth,
+	}, nil
+}
+
+func (s *Server) patchWorkspacePermissions(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var patch api.WorkspacePermissionPatch
+	if err := decodeBody(r, &patch); err != nil {
+		return nil, err
+	}
+
+	if patch.Public != nil {
+		w.public = *patch.Public
+	}
+	for account, permission := range patch.Authorizations {
+		user, err := s.findUser(account)
+		if err != nil {
+			return nil, err
+		}
+		switch permission {
+		case api.NoPermission:
+			delete(w.auth, user.ID)
+		case api.Read, api.Write, api.FullControl:
+			w.auth[user.ID] = permission
+		default:
+			return nil, errorf(http.StatusBadRequest, "invalid permission %q", permission)
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) listWorkspaceDatasets(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+	results, err := parseBool(r, "results")
+	if err != nil {
+		return nil, err
+	}
+	committed, err := parseBool(r, "committed")
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []api.Dataset
+	for _, d := range s.datasets {
+		if d.Workspace.ID != w.ID {
+			continue
+		}
+		if results != nil && (d.SourceExecution != "") != *results {
+			continue
+		}
+		if committed != nil && !d.Committed.IsZero() != *committed {
+			continue
+		}
+		if matchesText(r, d.Name, d.Description) {
+			matches = append(matches, viewDataset(d))
+		}
+	}
+
+	start, end, next, err := paginate(r, len(matches))
+	if err != nil {
+		return nil, err
+	}
+	return api.DatasetPage{Data: append([]api.Dataset{}, matches[start:end]...), NextCursor: next}, nil
+}
+
+func (s *Server) listWorkspaceExperiments(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*experiment
+	for _, e := range s.experiments {
+		if e.Workspace.ID == w.ID && matchesText(r, e.Name, e.Description) {
+			matches = append(matches, e)
+		}
+	}
+
+	start, end, next, err := paginate(r, len(matches))
+	if err != nil {
+		return nil, err
+	}
+
+	page := api.ExperimentPage{Data: []api.Experiment{}, NextCursor: next}
+	for _, e := range matches[start:end] {
+		page.Data = append(page.Data, s.viewExperiment(e))
+	}
+	return page, nil
+}
+
+func (s *Server) listWorkspaceGroups(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []api.Group
+	for _, g := range s.groups {
+		if g.Workspace.ID == w.ID && matchesText(r, g.Name, g.Description) {
+			matches = append(matches, g.Group)
+		}
+	}
+
+	start, end, next, err := paginate(r, len(matches))
+	if err != nil {
+		return nil, err
+	}
+	return api.GroupPage{Data: append([]api.Group{}, matches[start:end]...), NextCursor: next}, nil
+}
+
+func (s *Server) listWorkspaceImages(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []api.Image
+	for _, i := range s.images {
+		if i.Workspace.ID == w.ID && matchesText(r, i.Name, i.Description) {
+			matches = append(matches, *i)
+		}
+	}
+
+	start, end, next, err := paginate(r, len(matches))
+	if err != nil {
+		return nil, err
+	}
+	return api.ImagePage{Data: append([]api.Image{}, matches[start:end]...), NextCursor: next}, nil
+}
+
+func (s *Server) listSecrets(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := api.Secrets{Data: []api.Secret{}}
+	for _, secret := range w.secrets {
+		result.Data = append(result.Data, secret.Secret)
+	}
+	return result, nil
+}
+
+func (s *Server) getSecret(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+	i, err := w.findSecret(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return w.secrets[i].Secret, nil
+}
+
+func (s *Server) deleteSecret(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+	i, err := w.findSecret(args[1])
+	if err != nil {
+		return nil, err
+	}
+	w.secrets = append(w.secrets[:i], w.secrets[i+1:]...)
+	return nil, nil
+}
+
+func (s *Server) readSecret(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+	i, err := w.findSecret(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return rawResponse{contentType: "application/octet-stream", body: w.secrets[i].value}, nil
+}
+
+func (s *Server) putSecret(r *http.Request, args []string) (interface{}, error) {
+	w, err := s.findWorkspace(args[0])
+	if err != nil {
+		return nil, err
+	}
+	value, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "failed to read secret: %v", err)
+	}
+
+	t := now()
+	if i, err := w.findSecret(args[1]); err == nil {
+		w.secrets[i].value = value
+		w.secrets[i].Updated = t
+		return w.secrets[i].Secret, nil
+	}
+
+	secret := &secret{Secret: api.Secret{Name: args[1], Created: t, Updated: t}, value: value}
+	w.secrets = append(w.secrets, secret)
+	return secret.Secret, nil
+}